@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/inlined/goldmine/pkg/debug"
+	"github.com/inlined/goldmine/pkg/solver"
+	"github.com/inlined/goldmine/pkg/stats"
+)
+
+// configsFlag collects every --config as a flag.Value
+type configsFlag []*config
+
+func (f *configsFlag) String() string {
+	var s []string
+	for _, c := range *f {
+		s = append(s, c.String())
+	}
+	return strings.Join(s, "; ")
+}
+
+// Set implements flag.Value
+func (f *configsFlag) Set(s string) error {
+	c, err := parseConfig(s)
+	if err != nil {
+		return err
+	}
+	*f = append(*f, c)
+	return nil
+}
+
+// benchResult summarizes the runs of one config
+type benchResult struct {
+	config *config
+	scores stats.Summary
+	// perGeneration is the average time each generation took
+	perGeneration time.Duration
+	// p is the chance the config is only behind the best by luck, or -1
+	// for the best config itself
+	p float64
+}
+
+// bench implements `goldmine bench`, which solves a set of maps with
+// several configs and seeds and compares how well each config did.
+func bench(args []string) {
+	var configs configsFlag
+	flags := flag.NewFlagSet("bench", flag.ExitOnError)
+	flags.Var(&configs, "config", `solver flags to benchmark, such as "--strategy=graph --mutation_rate=0.05"; repeat to compare several`)
+	input := flags.String("input", "", "map file, such as data/mastertests.txt, or blank for stdin")
+	seeds := flags.Int("seeds", 5, "number of runs of each config, each with its own seed")
+	seed := flags.Int64("seed", 1, "seed that every run's seed is derived from")
+	generations := flags.Int("generations", 100, "most generations to run on each map; 0 for no limit")
+	timeout := flags.Duration("timeout", 0, "time to spend on each run over every map; 0 for no limit")
+	workers := flags.Int("workers", runtime.NumCPU(), "number of maps to solve at once")
+	output := flags.String("output", "", "output file for the table or blank for stdout")
+	csvOutput := flags.String("csv", "", "file to also write the results to as CSV")
+	flags.Var(debug.Flag, "debug", "Debug location or file descriptor; empty to turn off debugging")
+	flags.Parse(args)
+
+	if *generations == 0 && *timeout == 0 {
+		panic("goldmine bench requires a --generations or --timeout budget")
+	}
+	if *seeds < 1 {
+		panic(fmt.Sprintf("--seeds must be at least 1, got %d", *seeds))
+	}
+	if len(configs) == 0 {
+		configs.Set("")
+	}
+	var err error
+	var out io.WriteCloser = os.Stdout
+	if *output != "" {
+		if out, err = os.Create(*output); err != nil {
+			panic(fmt.Sprintf("Unexpected error opening %s: %s", *output, err))
+		}
+		defer out.Close()
+	}
+
+	ms := readMaps(*input)
+	runs := make([][]run, len(configs))
+	for i, c := range configs {
+		for j := 0; j < *seeds; j++ {
+			// Every config gets the same seeds so they solve the same way
+			// where they don't differ
+			ctx, cancel := context.Background(), context.CancelFunc(func() {})
+			if *timeout > 0 {
+				ctx, cancel = context.WithTimeout(ctx, *timeout)
+			}
+			r := c.solve(ctx, ms, solver.DeriveSeed(*seed, j), *generations, *workers)
+			cancel()
+			fmt.Fprintf(debug.Out, "Config %d seed %d scored %d in %d generations\n", i, j, r.score, r.generations)
+			runs[i] = append(runs[i], r)
+		}
+	}
+
+	results := compare(configs, runs)
+	writeBenchTable(out, results)
+	if *csvOutput != "" {
+		f, err := os.Create(*csvOutput)
+		if err != nil {
+			panic(fmt.Sprintf("Unexpected error opening %s: %s", *csvOutput, err))
+		}
+		defer f.Close()
+		if err := writeBenchCSV(f, results); err != nil {
+			panic(fmt.Sprintf("Unexpected error writing %s: %s", *csvOutput, err))
+		}
+	}
+}
+
+// compare summarizes each config's runs and tests them against the
+// config with the best mean score
+func compare(configs []*config, runs [][]run) []benchResult {
+	scores := make([][]float64, len(configs))
+	results := make([]benchResult, len(configs))
+	best := 0
+	for i, c := range configs {
+		var generations int
+		var elapsed time.Duration
+		for _, r := range runs[i] {
+			scores[i] = append(scores[i], float64(r.score))
+			generations += r.generations
+			elapsed += r.elapsed
+		}
+		results[i] = benchResult{config: c, scores: stats.Summarize(scores[i])}
+		if generations != 0 {
+			results[i].perGeneration = elapsed / time.Duration(generations)
+		}
+		if results[i].scores.Mean > results[best].scores.Mean {
+			best = i
+		}
+	}
+	for i := range results {
+		results[i].p = -1
+		if i != best {
+			_, results[i].p = stats.WelchT(scores[best], scores[i])
+		}
+	}
+	return results
+}
+
+func writeBenchTable(w io.Writer, results []benchResult) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "#\truns\tmean\tmedian\tstddev\ttime/generation\tp vs best\tconfig")
+	for i, r := range results {
+		p := "best"
+		if r.p >= 0 {
+			p = fmt.Sprintf("%.3f", r.p)
+		}
+		fmt.Fprintf(tw, "%d\t%d\t%.1f\t%.1f\t%.1f\t%s\t%s\t%s\n", i, r.scores.N, r.scores.Mean, r.scores.Median, r.scores.StdDev, r.perGeneration.Round(time.Microsecond), p, r.config)
+	}
+	tw.Flush()
+}
+
+func writeBenchCSV(w io.Writer, results []benchResult) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"config", "runs", "mean", "median", "stddev", "seconds_per_generation", "p_vs_best"})
+	for _, r := range results {
+		p := ""
+		if r.p >= 0 {
+			p = strconv.FormatFloat(r.p, 'g', -1, 64)
+		}
+		cw.Write([]string{
+			r.config.String(),
+			strconv.Itoa(r.scores.N),
+			strconv.FormatFloat(r.scores.Mean, 'g', -1, 64),
+			strconv.FormatFloat(r.scores.Median, 'g', -1, 64),
+			strconv.FormatFloat(r.scores.StdDev, 'g', -1, 64),
+			strconv.FormatFloat(r.perGeneration.Seconds(), 'g', -1, 64),
+			p,
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
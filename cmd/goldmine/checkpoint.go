@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/inlined/goldmine/pkg/solver"
+)
+
+// checkpointer saves the progress of every map's solver to a file so
+// that a later run can --resume it. Maps may be saved concurrently.
+type checkpointer struct {
+	mu       sync.Mutex
+	path     string
+	interval time.Duration
+	last     time.Time
+	file     solver.CheckpointFile
+}
+
+// readCheckpoints loads a file written by a checkpointer, or returns an
+// empty file if path is blank.
+func readCheckpoints(path string) solver.CheckpointFile {
+	var f solver.CheckpointFile
+	if path == "" {
+		return f
+	}
+	in, err := os.Open(path)
+	if err != nil {
+		panic(fmt.Sprintf("Unexpected error opening %s: %s", path, err))
+	}
+	defer in.Close()
+	if f, err = solver.ReadCheckpointFile(in); err != nil {
+		panic(fmt.Sprintf("Could not read checkpoint %s: %s", path, err))
+	}
+	return f
+}
+
+// observe saves map i's solver whenever interval has passed since the
+// last write.
+func (c *checkpointer) observe(i int, s solver.Solver) solver.Observer {
+	return solver.ObserverFunc(func(e solver.Event) {
+		if _, ok := e.(solver.GenerationEvent); !ok || c.path == "" {
+			return
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if time.Since(c.last) >= c.interval {
+			c.record(i, s)
+			c.write()
+		}
+	})
+}
+
+// record saves map i's solver to be written by the next write. It must
+// be called from the goroutine running s.
+func (c *checkpointer) record(i int, s solver.Solver) {
+	c.file.Maps[i] = s.Checkpoint()
+}
+
+// write saves every map's latest checkpoint to the file
+func (c *checkpointer) write() {
+	if c.path == "" {
+		return
+	}
+	c.last = time.Now()
+
+	// Write somewhere else first so an interrupted write can't
+	// clobber the last good checkpoint.
+	tmp := c.path + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		panic(fmt.Sprintf("Unexpected error opening %s: %s", tmp, err))
+	}
+	err = solver.WriteCheckpointFile(out, c.file)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		panic(fmt.Sprintf("Could not write checkpoint %s: %s", tmp, err))
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		panic(fmt.Sprintf("Could not write checkpoint %s: %s", c.path, err))
+	}
+}
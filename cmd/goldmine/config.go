@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/inlined/genetics"
+
+	"github.com/inlined/goldmine/pkg/maps"
+	"github.com/inlined/goldmine/pkg/schedule"
+	"github.com/inlined/goldmine/pkg/solver"
+)
+
+// config is one way of running the solver, written as the command line
+// flags that choose it, such as "--strategy=graph --mutation_rate=0.05".
+// Flags that aren't given keep the solver's defaults.
+type config struct {
+	strategy         solver.Flag
+	selection        genetics.NaturalSelectionFlag
+	crossover        genetics.CrossoverFlag
+	mutation         genetics.MutationFlag
+	populationSize   int
+	replacementCount int
+	mutationRate     float64
+}
+
+// parseConfig reads a config from the solver's command line flags
+func parseConfig(s string) (*config, error) {
+	c := &config{}
+	flags := flag.NewFlagSet("config", flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	flags.Var(&c.strategy, "strategy", "")
+	flags.Var(&c.selection, "selection", "")
+	flags.Var(&c.crossover, "crossover", "")
+	flags.Var(&c.mutation, "mutation", "")
+	// The solver's own flags hold their defaults until flag.Parse
+	flags.IntVar(&c.populationSize, "generation_size", *populationSize, "")
+	flags.IntVar(&c.replacementCount, "replacement_count", *replacementCount, "")
+	flags.Float64Var(&c.mutationRate, "mutation_rate", *mutationRate, "")
+	if err := flags.Parse(strings.Fields(s)); err != nil {
+		return nil, fmt.Errorf("parseConfig(%s): %s", s, err)
+	}
+	if flags.NArg() != 0 {
+		return nil, fmt.Errorf("parseConfig(%s): unexpected argument %s", s, flags.Arg(0))
+	}
+	return c, nil
+}
+
+// flags lists the name and value of each flag that chooses c
+func (c *config) flags() [][2]string {
+	flags := [][2]string{{"strategy", c.strategy.String()}}
+	for _, f := range []struct {
+		name string
+		flag.Value
+	}{{"selection", &c.selection}, {"crossover", &c.crossover}, {"mutation", &c.mutation}} {
+		if v := f.String(); v != "" {
+			flags = append(flags, [2]string{f.name, v})
+		}
+	}
+	return append(flags,
+		[2]string{"generation_size", fmt.Sprint(c.populationSize)},
+		[2]string{"replacement_count", fmt.Sprint(c.replacementCount)},
+		[2]string{"mutation_rate", fmt.Sprintf("%g", c.mutationRate)},
+	)
+}
+
+// String writes c as the flags that choose it
+func (c *config) String() string {
+	var args []string
+	for _, f := range c.flags() {
+		args = append(args, fmt.Sprintf("--%s=%s", f[0], f[1]))
+	}
+	return strings.Join(args, " ")
+}
+
+// commandLine writes c like String but with every value quoted, so that
+// values like graph(alleles=3) reach the solver through a shell intact
+func (c *config) commandLine() string {
+	var args []string
+	for _, f := range c.flags() {
+		args = append(args, fmt.Sprintf("--%s='%s'", f[0], strings.Replace(f[1], "'", `'\''`, -1)))
+	}
+	return strings.Join(args, " ")
+}
+
+// run is what came of solving a set of maps once
+type run struct {
+	score       int
+	generations int
+	elapsed     time.Duration
+}
+
+// solve runs c on every map with seeds derived from seed, for up to
+// generations generations per map or until ctx is done. workers maps
+// are solved at once.
+func (c *config) solve(ctx context.Context, ms []maps.Map, seed int64, generations, workers int) run {
+	evolver := genetics.Evolver{
+		ReplacementCount: c.replacementCount,
+		MutationRate:     float32(c.mutationRate),
+		Selector:         c.selection.Get(),
+		Crossover:        c.crossover.Get(),
+		Mutator:          c.mutation.Get(),
+	}
+	solvers := make([]solver.Solver, len(ms))
+	jobs := make([]schedule.Job, len(ms))
+	for i, m := range ms {
+		mapSeed := solver.DeriveSeed(seed, i)
+		solvers[i] = c.strategy.New(solver.Input{
+			Map:     m,
+			Evolver: evolver,
+			Rand:    solver.NewRand(mapSeed),
+			Seed:    mapSeed,
+		})
+		jobs[i] = schedule.Job{Solver: solvers[i], Generations: generations}
+	}
+
+	var scheduler schedule.Flag
+	pool := schedule.Pool{
+		Workers:   workers,
+		Slice:     *slice,
+		Scheduler: scheduler.Get(),
+		Rand:      solver.NewRand(solver.DeriveSeed(seed, len(ms))),
+		Start: func(i int) {
+			if err := solvers[i].Init(c.populationSize); err != nil {
+				panic(fmt.Sprintf("Could not initialize %s: %s", c, err))
+			}
+		},
+	}
+	pool.Run(ctx, jobs)
+
+	var r run
+	for _, s := range solvers {
+		res := s.Result()
+		r.score += res.Score
+		r.generations += res.Generations
+		r.elapsed += res.Elapsed
+	}
+	return r
+}
+
+// readMaps reads every map from a file, or stdin if path is blank
+func readMaps(path string) []maps.Map {
+	var err error
+	var in io.Reader = os.Stdin
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			panic(fmt.Sprintf("Unexpected error opening %s: %s", path, err))
+		}
+		defer f.Close()
+		in = f
+	}
+	var ms []maps.Map
+	r := maps.NewReader(in)
+	var m maps.Map
+	for m, err = r.Next(); err == nil; m, err = r.Next() {
+		ms = append(ms, m)
+	}
+	if err != io.EOF {
+		panic(fmt.Sprintf("Unexpected error reading maps: %s", err))
+	}
+	return ms
+}
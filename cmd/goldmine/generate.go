@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	mrand "math/rand"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/inlined/rand"
+
+	"github.com/inlined/goldmine/pkg/maps"
+)
+
+// digitWeights parses a comma separated list of up to ten weights for
+// the digits 0-9 as a flag.Value
+type digitWeights [10]int
+
+func (w *digitWeights) String() string {
+	var s []string
+	for _, x := range w {
+		s = append(s, strconv.Itoa(x))
+	}
+	return strings.Join(s, ",")
+}
+
+// Set implements flag.Value
+func (w *digitWeights) Set(s string) error {
+	parts := strings.Split(s, ",")
+	if len(parts) > len(w) {
+		return fmt.Errorf("digitWeights.Set(%s): expected at most %d weights", s, len(w))
+	}
+	*w = digitWeights{}
+	for i, p := range parts {
+		x, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil {
+			return fmt.Errorf("digitWeights.Set(%s): %s", s, err)
+		}
+		w[i] = x
+	}
+	return nil
+}
+
+// generate implements `goldmine generate`, which writes random maps
+// in the same format maps.Reader parses.
+func generate(args []string) {
+	opts := maps.DefaultGenerateOptions
+	weights := digitWeights(opts.DigitWeights)
+
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	count := flags.Int("count", 1, "number of maps to generate")
+	flags.IntVar(&opts.Rows, "rows", opts.Rows, "number of rows in each map")
+	flags.IntVar(&opts.Cols, "cols", opts.Cols, "number of columns in each map")
+	flags.IntVar(&opts.StepsAllowed, "steps", opts.StepsAllowed, "number of steps allowed in each map")
+	flags.Float64Var(&opts.WallDensity, "wall_density", opts.WallDensity, "fraction of cells that are walls")
+	flags.Float64Var(&opts.DigitDensity, "digit_density", opts.DigitDensity, "fraction of cells that hold digits")
+	flags.IntVar(&opts.Pickaxes, "pickaxes", opts.Pickaxes, "number of pickaxes in each map")
+	flags.Var(&weights, "digit_weights", "comma separated relative frequency of the digits 0-9; blank for 1-9 equally")
+	seed := flags.Int64("seed", 0, "random seed; 0 for a random map every run")
+	output := flags.String("output", "", "output file or blank for stdout")
+	flags.Parse(args)
+	opts.DigitWeights = weights
+
+	var err error
+	var out io.WriteCloser = os.Stdout
+	if *output != "" {
+		if out, err = os.Create(*output); err != nil {
+			panic(fmt.Sprintf("Unexpected error opening %s: %s", *output, err))
+		}
+		defer out.Close()
+	}
+
+	var r rand.Rand = rand.New()
+	if *seed != 0 {
+		r = mrand.New(mrand.NewSource(*seed))
+	}
+	for i := 0; i < *count; i++ {
+		m, err := maps.Generate(r, opts)
+		if err != nil {
+			panic(fmt.Sprintf("Could not generate map: %s", err))
+		}
+		if i != 0 {
+			fmt.Fprintln(out)
+		}
+		fmt.Fprint(out, m)
+	}
+}
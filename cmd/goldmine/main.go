@@ -1,21 +1,30 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
+	"runtime"
+	"time"
 
 	"github.com/inlined/genetics"
-	"github.com/inlined/rand"
 
 	"github.com/inlined/goldmine/pkg/debug"
 	"github.com/inlined/goldmine/pkg/maps"
+	"github.com/inlined/goldmine/pkg/schedule"
 	"github.com/inlined/goldmine/pkg/solver"
 
 	// Import for flag side-effects
+	_ "github.com/inlined/goldmine/pkg/behavioral"
 	_ "github.com/inlined/goldmine/pkg/bruteforce"
+	_ "github.com/inlined/goldmine/pkg/exact"
 	_ "github.com/inlined/goldmine/pkg/graph"
+	_ "github.com/inlined/goldmine/pkg/island"
+	_ "github.com/inlined/goldmine/pkg/portfolio"
 )
 
 var (
@@ -23,6 +32,7 @@ var (
 	crossoverFlag genetics.CrossoverFlag
 	mutationFlag  genetics.MutationFlag
 	solverFlag    solver.Flag
+	schedulerFlag schedule.Flag
 
 	populationSize   = flag.Int("generation_size", 50, "number of chromosomes in each generation")
 	replacementCount = flag.Int("replacement_count", 20, "number of chromosomes to replace each generation")
@@ -30,18 +40,82 @@ var (
 
 	input  = flag.String("input", "", "input file or blank for stdin")
 	output = flag.String("output", "", "output file or blank for stdout")
+
+	timeout     = flag.Duration("timeout", 0, "total time to spend solving all maps; 0 for no limit")
+	generations = flag.Int("generations", 100000, "most generations to run on each map; 0 for no limit")
+	workers     = flag.Int("workers", runtime.NumCPU(), "number of maps to solve at once")
+	slice       = flag.Int("slice", 100, "generations to run on a map each time the scheduler picks it")
+
+	islands           = flag.Int("islands", 0, "populations of --strategy to evolve in parallel with migration; 0 for just one")
+	migrationInterval = flag.Int("migration_interval", 50, "generations each island runs between migrations")
+	migrants          = flag.Int("migrants", 2, "fittest chromosomes each island sends per migration")
+	topology          = flag.String("topology", "ring", "where migrants go: ring or full")
+
+	checkpoint         = flag.String("checkpoint", "", "file to periodically save solver progress to")
+	checkpointInterval = flag.Duration("checkpoint_interval", time.Minute, "how often to write --checkpoint")
+	resume             = flag.String("resume", "", "checkpoint file to resume solving from")
+	warmStart          = flag.String("warm-start", "", "answer file from an earlier run whose paths seed each map's search")
+
+	seed           = flag.Int64("seed", 0, "seed for every map's random numbers; 0 picks one from the clock. Runs with --timeout may still differ")
+	answerMetadata = flag.Bool("answer_metadata", false, "write # comments recording seeds and strategy to the output, which strict answer checkers reject")
+
+	alternatives = flag.Int("alternatives", 1, "distinct paths to write for each map, best first; all but the best are written as # comments so the output is still an answer file")
+
+	explain = flag.Bool("explain", false, "print a per-step trace of each best path to the debug output")
 )
 
 func init() {
 	flag.Var(&selectionFlag, "selection", "algorithm for selecting parents")
 	flag.Var(&crossoverFlag, "crossover", "genetic crossover strategy for creating children")
 	flag.Var(&mutationFlag, "mutation", "mutations new children may exhibit")
-	flag.Var(&solverFlag, "strategy", "Strategy used to solve goldmine maps")
+	flag.Var(&solverFlag, "strategy", "Strategy used to solve goldmine maps, such as graph(alleles=3) or island(solver=graph,islands=8); help lists them all with their options")
+	flag.Var(&schedulerFlag, "scheduler", "how to pick the map to work on next: round-robin, score, or improvement")
 	flag.Var(debug.Flag, "debug", "Debug location or file descriptor; empty to turn off debugging")
 }
 
+// subcommands run instead of the solver when named as the first argument
+var subcommands = map[string]func(args []string){
+	"bench":    bench,
+	"generate": generate,
+	"render":   renderMaps,
+	"score":    score,
+	"tune":     tune,
+}
+
+// parseArgs parses the command line flags, then wraps --strategy in an
+// island solver if --islands asks for one
+func parseArgs(args []string) {
+	if err := flag.CommandLine.Parse(args); err != nil {
+		panic(fmt.Sprintf("Could not parse flags: %s", err))
+	}
+	if *islands == 0 || solverFlag.Help() {
+		return
+	}
+	if solverFlag.Name() == "island" {
+		panic("--islands can't be used with --strategy=island; set its islands option instead")
+	}
+	spec := fmt.Sprintf("island(solver=%s,islands=%d,interval=%d,migrants=%d,topology=%s)", solverFlag.String(), *islands, *migrationInterval, *migrants, *topology)
+	if err := solverFlag.Set(spec); err != nil {
+		panic(fmt.Sprintf("Could not use --islands: %s", err))
+	}
+}
+
 func main() {
-	flag.Parse()
+	if len(os.Args) > 1 {
+		if cmd, ok := subcommands[os.Args[1]]; ok {
+			cmd(os.Args[2:])
+			return
+		}
+	}
+	parseArgs(os.Args[1:])
+	if solverFlag.Help() {
+		solver.DefaultRegistry.Help(os.Stdout)
+		return
+	}
+
+	if *alternatives < 1 {
+		panic(fmt.Sprintf("--alternatives must be at least 1, got %d", *alternatives))
+	}
 
 	var err error
 	var in io.Reader = os.Stdin
@@ -50,6 +124,11 @@ func main() {
 			panic(fmt.Sprintf("Unexpected error opening %s: %s", *input, err))
 		}
 	}
+	// Keep a copy of the maps to pair the warm start answers with
+	var mapText bytes.Buffer
+	if *warmStart != "" {
+		in = io.TeeReader(in, &mapText)
+	}
 	var out io.WriteCloser = os.Stdout
 	if *output != "" {
 		if out, err = os.Create(*output); err != nil {
@@ -66,39 +145,187 @@ func main() {
 		Mutator:          mutationFlag.Get(),
 	}
 
-	var solvers []solver.Solver
+	resumed := readCheckpoints(*resume)
+	if *resume != "" && resumed.Strategy != solverFlag.String() {
+		panic(fmt.Sprintf("Checkpoint %s was made by --strategy=%s, not %s", *resume, resumed.Strategy, solverFlag.String()))
+	}
+	switch {
+	case *resume != "" && *seed == 0:
+		*seed = resumed.Seed
+	case *seed == 0:
+		*seed = time.Now().UnixNano()
+	}
+	fmt.Fprintf(debug.Out, "Seed %d\n", *seed)
+	if *answerMetadata {
+		fmt.Fprintf(out, "%s --seed=%d --strategy=%s\n", maps.AnswerComment, *seed, solverFlag.String())
+	}
+
+	var inputs []maps.Map
 	r := maps.NewReader(in)
 	var m maps.Map
 	for m, err = r.Next(); err == nil; m, err = r.Next() {
+		inputs = append(inputs, m)
+	}
+	if err != nil && err != io.EOF {
+		panic(fmt.Sprintf("Unexpected error reading maps: %s", err))
+	}
+	if *resume != "" && len(resumed.Maps) != len(inputs) {
+		panic(fmt.Sprintf("Checkpoint %s has %d maps but the input has %d", *resume, len(resumed.Maps), len(inputs)))
+	}
+	warm := readWarmStart(*warmStart, &mapText)
+
+	var solvers []solver.Solver
+	var inputSeeds []int64
+	for i, m := range inputs {
+		// Each map's numbers only depend on its position so that runs
+		// can be reproduced even if maps are solved in another order.
+		mapSeed := solver.DeriveSeed(*seed, i)
+		rng := solver.NewRand(mapSeed)
+		// Maps the checkpointed run never recorded start like a fresh run
+		if i < len(resumed.Maps) && resumed.Maps[i].Seed != 0 {
+			// Don't replay the random numbers the checkpointed run already used
+			mapSeed = resumed.Maps[i].Seed
+			rng = solver.NewRand(solver.DeriveSeed(mapSeed, resumed.Maps[i].Generation))
+		}
 		input := solver.Input{
 			Evolver: evolver,
 			Map:     m,
-			Rand:    rand.New(),
+			Rand:    rng,
+			Seed:    mapSeed,
+
+			Alternatives: *alternatives,
+		}
+		if warm != nil {
+			input.WarmStart = validWarmStart(i, m, warm[i])
 		}
 		solvers = append(solvers, solverFlag.New(input))
+		inputSeeds = append(inputSeeds, mapSeed)
 	}
 
-	if err != nil && err != io.EOF {
-		panic(fmt.Sprintf("Unexpected error reading maps: %s", err))
+	saver := &checkpointer{
+		path:     *checkpoint,
+		interval: *checkpointInterval,
+		last:     time.Now(),
+		file: solver.CheckpointFile{
+			Strategy: solverFlag.String(),
+			Seed:     *seed,
+			Maps:     make([]solver.Checkpoint, len(solvers)),
+		},
+	}
+	copy(saver.file.Maps, resumed.Maps)
+
+	// Stop solving early on interrupt; the best paths so far are still written.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		cancel()
+	}()
+	if *timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, *timeout)
+		defer cancelTimeout()
+	}
+	if *generations == 0 && *timeout == 0 {
+		fmt.Fprintln(debug.Out, "No --generations or --timeout limit; solving until interrupted")
 	}
 
-	// TODO: Parallel solve each map and add strategy for choosing
-	// which map to further investigate.
-	const stepCount = 100
-	const numSteps = 1000
-	const sampleRate = 10
+	jobs := make([]schedule.Job, len(solvers))
 	for i, s := range solvers {
-		fmt.Fprintf(debug.Out, "Map %d\n", i)
-		if err := s.Init(*populationSize); err != nil {
-			panic(fmt.Sprintf("Could not initializes solver:%s", err))
+		s.Subscribe(logProgress(i))
+		s.Subscribe(saver.observe(i, s))
+		jobs[i] = schedule.Job{Solver: s, Generations: *generations}
+		if i < len(resumed.Maps) && *generations != 0 {
+			jobs[i].Generations -= resumed.Maps[i].Generation
+			jobs[i].Complete = jobs[i].Generations <= 0
 		}
-		for x := 0; x < numSteps; x++ {
-			s.Step(stepCount)
-			if (x+1)%sampleRate == 0 {
-				fmt.Fprintf(debug.Out, "%d,", s.Score())
+	}
+	pool := schedule.Pool{
+		Workers:   *workers,
+		Slice:     *slice,
+		Scheduler: schedulerFlag.Get(),
+		Rand:      solver.NewRand(solver.DeriveSeed(*seed, len(solvers))),
+		Start: func(i int) {
+			s := solvers[i]
+			fmt.Fprintf(debug.Out, "Map %d starting (seed %d)\n", i, inputSeeds[i])
+			if err := s.Init(*populationSize); err != nil {
+				panic(fmt.Sprintf("Could not initializes solver:%s", err))
 			}
+			if i < len(resumed.Maps) {
+				if err := s.Restore(resumed.Maps[i]); err != nil {
+					panic(fmt.Sprintf("Could not resume map %d: %s", i, err))
+				}
+				fmt.Fprintf(debug.Out, "Map %d resumed at generation %d with score %d\n", i, resumed.Maps[i].Generation, s.Score())
+			}
+		},
+	}
+	pool.Run(ctx, jobs)
+
+	for i, s := range solvers {
+		saver.record(i, s)
+	}
+	saver.write()
+
+	for i, s := range solvers {
+		res := s.Result()
+		fmt.Fprintf(debug.Out, "Map %d (seed %d) ran %d generations and %d evaluations in %s", i, inputSeeds[i], res.Generations, res.Evaluations, res.Elapsed.Round(time.Millisecond))
+		if res.Optimal {
+			fmt.Fprintf(debug.Out, "\nProved optimal")
+		}
+		bound := maps.Analyze(inputs[i]).UpperBound
+		if res.UpperBound != 0 && res.UpperBound < bound {
+			bound = res.UpperBound
+		}
+		fmt.Fprintf(debug.Out, "\nScore %d of at most %d", res.Score, bound)
+		if bound != 0 {
+			fmt.Fprintf(debug.Out, " (%.1f%%)", 100*float64(res.Score)/float64(bound))
+		}
+		fmt.Fprintf(debug.Out, ", found in generation %d", res.FoundAt)
+		best := res.Path
+		if best == nil {
+			// Nothing was evaluated, but any legal path beats no answer
+			best.Pad(inputs[i])
+		}
+		fmt.Fprintf(debug.Out, "\n%s\n", best)
+		if *explain {
+			for _, e := range best.Trace(inputs[i]) {
+				fmt.Fprintln(debug.Out, e)
+			}
+		}
+		fmt.Fprintln(debug.Out)
+		if *answerMetadata {
+			fmt.Fprintf(out, "%s map %d seed=%d score=%d\n", maps.AnswerComment, i, inputSeeds[i], res.Score)
+		}
+		fmt.Fprintln(out, best)
+		rank := 1
+		for _, a := range res.Alternatives {
+			if rank == *alternatives {
+				break
+			}
+			if a.Path.String() == best.String() {
+				continue
+			}
+			rank++
+			fmt.Fprintf(out, "%s map %d alternative %d score=%d %s\n", maps.AnswerComment, i, rank, a.Score, a.Path)
 		}
-		fmt.Fprintf(debug.Out, "\n%s\n\n", s.Path(s.Best()))
-		fmt.Fprintln(out, s.Path(s.Best()))
 	}
 }
+
+// logProgress writes map i's solver events to the debug output
+func logProgress(i int) solver.Observer {
+	const sampleRate = 1000
+	return solver.ObserverFunc(func(e solver.Event) {
+		switch e := e.(type) {
+		case solver.InitEvent:
+			if e.MeaningfulPaths != 0 {
+				fmt.Fprintf(debug.Out, "Map %d has %d points of interest and %d meaningful paths\n", i, e.PointsOfInterest, e.MeaningfulPaths)
+			}
+		case solver.GenerationEvent:
+			if (e.Generation+1)%sampleRate == 0 {
+				fmt.Fprintf(debug.Out, "Map %d generation %d: %d\n", i, e.Generation+1, e.Best)
+			}
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	mrand "math/rand"
+	"strings"
+	"testing"
+
+	"github.com/inlined/genetics"
+	"github.com/inlined/goldmine/pkg/maps"
+	"github.com/inlined/goldmine/pkg/solver"
+)
+
+func TestIslandFlags(t *testing.T) {
+	r := maps.NewReader(strings.NewReader(`=4,5,8
+		w...1
+		..s.9
+		2d1..
+		.w..3`))
+	m, err := r.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		tag     string
+		args    []string
+		want    string
+		members int
+	}{
+		{
+			tag:  "no islands",
+			args: []string{"--strategy=graph", "--islands=0"},
+			want: "graph",
+		}, {
+			tag:     "flags",
+			args:    []string{"--strategy=bruteforce(padding=2)", "--islands=3", "--migration_interval=7", "--migrants=1", "--topology=full"},
+			want:    "island(solver=bruteforce(padding=2),islands=3,interval=7,migrants=1,topology=full)",
+			members: 3,
+		}, {
+			tag:     "spec",
+			args:    []string{"--strategy=island(solver=graph,islands=2,interval=5)", "--islands=0"},
+			want:    "island(solver=graph,islands=2,interval=5)",
+			members: 2,
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			parseArgs(test.args)
+			if got := solverFlag.String(); got != test.want {
+				t.Errorf("--strategy is %s; want %s", got, test.want)
+			}
+
+			var selection genetics.NaturalSelectionFlag
+			var crossover genetics.CrossoverFlag
+			var mutation genetics.MutationFlag
+			s := solverFlag.New(solver.Input{
+				Map: m,
+				Evolver: genetics.Evolver{
+					ReplacementCount: 5,
+					MutationRate:     0.1,
+					Selector:         selection.Get(),
+					Crossover:        crossover.Get(),
+					Mutator:          mutation.Get(),
+				},
+				Rand: mrand.New(mrand.NewSource(1)),
+			})
+			if err := s.Init(10); err != nil {
+				t.Fatal(err)
+			}
+			if got := len(s.Checkpoint().Members); got != test.members {
+				t.Errorf("%s has %d islands; want %d", test.want, got, test.members)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/inlined/goldmine/pkg/maps"
+	"github.com/inlined/goldmine/pkg/render"
+)
+
+// renderMaps implements `goldmine render`, which draws each map with
+// its answer overlaid.
+func renderMaps(args []string) {
+	flags := flag.NewFlagSet("render", flag.ExitOnError)
+	input := flags.String("input", "", "map file or blank for stdin")
+	answers := flags.String("answers", "", "answer file with one path per map")
+	output := flags.String("output", "", "output file or blank for stdout; png and svg output must contain %d when rendering more than one map")
+	format := flags.String("format", "text", "one of text, png, or svg")
+	color := flags.Bool("color", false, "highlight the path with ANSI colors in text output")
+	flags.Parse(args)
+
+	var draw func(io.Writer, maps.Map, maps.Path) error
+	switch *format {
+	case "text":
+	case "png":
+		draw = render.PNG
+	case "svg":
+		draw = render.SVG
+	default:
+		panic(fmt.Sprintf("Unknown render format %s", *format))
+	}
+
+	var err error
+	var in io.Reader = os.Stdin
+	if *input != "" {
+		if in, err = os.Open(*input); err != nil {
+			panic(fmt.Sprintf("Unexpected error opening %s: %s", *input, err))
+		}
+	}
+	if *answers == "" {
+		panic("goldmine render requires --answers")
+	}
+	ans, err := os.Open(*answers)
+	if err != nil {
+		panic(fmt.Sprintf("Unexpected error opening %s: %s", *answers, err))
+	}
+	defer ans.Close()
+	r := maps.NewAnswerReader(in, ans)
+	var a maps.Answer
+	var i int
+	if draw != nil {
+		// Images get one file per map, so make sure every map has its own
+		// file before writing any of them
+		var all []maps.Answer
+		for a, err = r.Next(); err == nil; a, err = r.Next() {
+			all = append(all, a)
+		}
+		if err != io.EOF {
+			panic(fmt.Sprintf("Unexpected error reading answers: %s", err))
+		}
+		if len(all) > 1 && !strings.Contains(*output, "%d") {
+			panic("--output must contain %d to render more than one map as an image")
+		}
+		for i, a := range all {
+			renderImage(*output, i, draw, a)
+		}
+		return
+	}
+
+	var out io.WriteCloser = os.Stdout
+	if *output != "" {
+		if out, err = os.Create(*output); err != nil {
+			panic(fmt.Sprintf("Unexpected error opening %s: %s", *output, err))
+		}
+		defer out.Close()
+	}
+
+	opts := render.Options{Color: *color}
+	for a, err = r.Next(); err == nil; a, err = r.Next() {
+		fmt.Fprintf(out, "Map %d: %s\n", i, a.Path)
+		if err := render.Text(out, a.Map, a.Path, opts); err != nil {
+			panic(fmt.Sprintf("Unexpected error rendering map %d: %s", i, err))
+		}
+		fmt.Fprintln(out)
+		i++
+	}
+	if err != io.EOF {
+		panic(fmt.Sprintf("Unexpected error reading answers: %s", err))
+	}
+}
+
+// renderImage draws the ith answer to a file named by formatting
+// output with i if it contains %d, or to stdout if output is blank.
+func renderImage(output string, i int, draw func(io.Writer, maps.Map, maps.Path) error, a maps.Answer) {
+	var err error
+	var out io.WriteCloser = os.Stdout
+	if output != "" {
+		if strings.Contains(output, "%d") {
+			output = fmt.Sprintf(output, i)
+		}
+		if out, err = os.Create(output); err != nil {
+			panic(fmt.Sprintf("Unexpected error opening %s: %s", output, err))
+		}
+		defer out.Close()
+	}
+	if err := draw(out, a.Map, a.Path); err != nil {
+		panic(fmt.Sprintf("Unexpected error rendering map %d: %s", i, err))
+	}
+}
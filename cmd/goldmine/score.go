@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/inlined/goldmine/pkg/maps"
+)
+
+// score implements `goldmine score`, which checks an answer file
+// against the maps it answers and reports the points earned.
+func score(args []string) {
+	flags := flag.NewFlagSet("score", flag.ExitOnError)
+	input := flags.String("input", "", "map file or blank for stdin")
+	answers := flags.String("answers", "", "answer file with one path per map")
+	output := flags.String("output", "", "output file or blank for stdout")
+	flags.Parse(args)
+
+	var err error
+	var in io.Reader = os.Stdin
+	if *input != "" {
+		if in, err = os.Open(*input); err != nil {
+			panic(fmt.Sprintf("Unexpected error opening %s: %s", *input, err))
+		}
+	}
+	if *answers == "" {
+		panic("goldmine score requires --answers")
+	}
+	ans, err := os.Open(*answers)
+	if err != nil {
+		panic(fmt.Sprintf("Unexpected error opening %s: %s", *answers, err))
+	}
+	defer ans.Close()
+	var out io.WriteCloser = os.Stdout
+	if *output != "" {
+		if out, err = os.Create(*output); err != nil {
+			panic(fmt.Sprintf("Unexpected error opening %s: %s", *output, err))
+		}
+		defer out.Close()
+	}
+
+	total := 0
+	r := maps.NewAnswerReader(in, ans)
+	var a maps.Answer
+	var i int
+	for a, err = r.Next(); err == nil; a, err = r.Next() {
+		// Answers that stop early are legal; they just miss out on points.
+		err := a.Path.Validate(a.Map)
+		if pe, ok := err.(*maps.PathError); ok && pe.Reason != maps.TooShort {
+			fmt.Fprintf(out, "Map %d: 0 (invalid: %s)\n", i, err)
+		} else {
+			s := a.Path.Score(a.Map)
+			total += s
+			fmt.Fprintf(out, "Map %d: %d\n", i, s)
+		}
+		i++
+	}
+	if err != io.EOF {
+		panic(fmt.Sprintf("Unexpected error reading answers: %s", err))
+	}
+	fmt.Fprintf(out, "Total: %d\n", total)
+}
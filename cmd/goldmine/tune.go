@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/inlined/rand"
+
+	"github.com/inlined/goldmine/pkg/debug"
+	"github.com/inlined/goldmine/pkg/maps"
+	"github.com/inlined/goldmine/pkg/solver"
+)
+
+// span is a range of numbers parsed from "lo:hi" as a flag.Value
+type span struct {
+	lo, hi float64
+}
+
+func (s *span) String() string {
+	return fmt.Sprintf("%g:%g", s.lo, s.hi)
+}
+
+// Set implements flag.Value
+func (s *span) Set(v string) error {
+	parts := strings.Split(v, ":")
+	if len(parts) != 2 {
+		return fmt.Errorf("span.Set(%s): expected lo:hi", v)
+	}
+	var err error
+	if s.lo, err = strconv.ParseFloat(parts[0], 64); err != nil {
+		return fmt.Errorf("span.Set(%s): %s", v, err)
+	}
+	if s.hi, err = strconv.ParseFloat(parts[1], 64); err != nil {
+		return fmt.Errorf("span.Set(%s): %s", v, err)
+	}
+	if s.lo > s.hi {
+		return fmt.Errorf("span.Set(%s): %g is more than %g", v, s.lo, s.hi)
+	}
+	return nil
+}
+
+// uniform picks a number in the span
+func (s *span) uniform(r rand.Rand) float64 {
+	return s.lo + (s.hi-s.lo)*float64(r.Int31n(1<<30))/(1<<30)
+}
+
+// logUniform picks a number in the span, favoring each order of
+// magnitude equally. The span must be positive.
+func (s *span) logUniform(r rand.Rand) float64 {
+	l := span{math.Log(s.lo), math.Log(s.hi)}
+	return math.Exp(l.uniform(r))
+}
+
+// choices is a comma separated list of flag values
+type choices []string
+
+func (c *choices) String() string {
+	return strings.Join(*c, ",")
+}
+
+// Set implements flag.Value
+func (c *choices) Set(v string) error {
+	*c = strings.Split(v, ",")
+	return nil
+}
+
+// pick chooses one of the values, or one of known if there are none.
+// Blank stands for the flag's default.
+func (c choices) pick(r rand.Rand, known []string) string {
+	if len(c) == 0 {
+		c = known
+	}
+	return c[r.Int31n(int32(len(c)))]
+}
+
+// Values of the genetics flags tried when tune isn't given a list
+var (
+	knownSelections = []string{"", "TournamentSelection(2)", "TournamentSelection(3)", "TournamentSelection(5)"}
+	knownCrossovers = []string{"", "MultiPointCrossover(1)", "MultiPointCrossover(2)", "MultiPointCrossover(3)"}
+	knownMutations  = []string{"", "ScrambleMutation"}
+)
+
+// trial is a config being tuned and how well it has done so far
+type trial struct {
+	*config
+	score float64
+}
+
+// tune implements `goldmine tune`, which searches for the genetics flags
+// that help a strategy score the most on a set of training maps and
+// writes them as a command line.
+func tune(args []string) {
+	var strategy solver.Flag
+	var selections, crossovers, mutations choices
+	populationSizes := span{10, 200}
+	replacementFractions := span{0.1, 0.8}
+	mutationRates := span{0.001, 0.3}
+
+	flags := flag.NewFlagSet("tune", flag.ExitOnError)
+	flags.Var(&strategy, "strategy", "strategy to tune, such as graph(alleles=3)")
+	flags.Var(&selections, "selection", "comma separated --selection values to try; blank for the default and a few common ones")
+	flags.Var(&crossovers, "crossover", "comma separated --crossover values to try; blank for the default and a few common ones")
+	flags.Var(&mutations, "mutation", "comma separated --mutation values to try; blank for the default and a few common ones")
+	flags.Var(&populationSizes, "generation_size", "lo:hi range of --generation_size to try")
+	flags.Var(&replacementFractions, "replacement_fraction", "lo:hi range of --replacement_count to try, as a fraction of --generation_size")
+	flags.Var(&mutationRates, "mutation_rate", "lo:hi range of --mutation_rate to try")
+	method := flags.String("method", "halving", "halving to give the best half of the configs twice the generations each round, or random to give every config the same generations")
+	trials := flags.Int("trials", 16, "number of configs to try")
+	generations := flags.Int("generations", 100, "generations per map that the final configs are compared with")
+	seeds := flags.Int("seeds", 2, "number of seeds each config is scored with")
+	seed := flags.Int64("seed", 1, "seed for picking configs and for the runs")
+	workers := flags.Int("workers", runtime.NumCPU(), "number of maps to solve at once")
+	input := flags.String("input", "", "training map file or blank for stdin; tune on maps other than the ones you will solve")
+	output := flags.String("output", "", "output file for the best command line or blank for stdout")
+	flags.Var(debug.Flag, "debug", "Debug location or file descriptor; empty to turn off debugging")
+	flags.Parse(args)
+
+	if *trials < 1 || *seeds < 1 || *generations < 1 {
+		panic("goldmine tune needs at least one trial, seed, and generation")
+	}
+	if mutationRates.lo <= 0 || populationSizes.lo < 2 || replacementFractions.lo < 0 || replacementFractions.hi > 1 {
+		panic("goldmine tune needs a positive --mutation_rate, a --generation_size of at least 2, and a --replacement_fraction between 0 and 1")
+	}
+	var err error
+	var out io.WriteCloser = os.Stdout
+	if *output != "" {
+		if out, err = os.Create(*output); err != nil {
+			panic(fmt.Sprintf("Unexpected error opening %s: %s", *output, err))
+		}
+		defer out.Close()
+	}
+
+	ms := readMaps(*input)
+	r := solver.NewRand(*seed)
+	candidates := make([]*trial, *trials)
+	for i := range candidates {
+		size := int(populationSizes.uniform(r) + 0.5)
+		replace := int(float64(size)*replacementFractions.uniform(r) + 0.5)
+		if replace >= size {
+			replace = size - 1
+		}
+		args := []string{
+			"--strategy=" + strategy.String(),
+			fmt.Sprintf("--generation_size=%d", size),
+			fmt.Sprintf("--replacement_count=%d", replace),
+			fmt.Sprintf("--mutation_rate=%.4g", mutationRates.logUniform(r)),
+		}
+		for _, f := range []struct {
+			name string
+			choices
+			known []string
+		}{
+			{"selection", selections, knownSelections},
+			{"crossover", crossovers, knownCrossovers},
+			{"mutation", mutations, knownMutations},
+		} {
+			if v := f.pick(r, f.known); v != "" {
+				args = append(args, fmt.Sprintf("--%s=%s", f.name, v))
+			}
+		}
+		c, err := parseConfig(strings.Join(args, " "))
+		if err != nil {
+			panic(fmt.Sprintf("Could not try config: %s", err))
+		}
+		candidates[i] = &trial{config: c}
+	}
+
+	var best *trial
+	switch *method {
+	case "random":
+		best = halve(candidates, ms, 0, *generations, *seed, *seeds, *workers)
+	case "halving":
+		// Halve until two configs are left for the full generations
+		rounds := 0
+		for n := 2; n < *trials; n *= 2 {
+			rounds++
+		}
+		best = halve(candidates, ms, rounds, *generations, *seed, *seeds, *workers)
+	default:
+		panic(fmt.Sprintf("Unknown tuning method %s", *method))
+	}
+
+	fmt.Fprintf(debug.Out, "Best config scored %.1f\n", best.score)
+	fmt.Fprintf(out, "goldmine %s\n", best.commandLine())
+}
+
+// halve scores every trial, then keeps the better half and scores them
+// again with twice the generations, for rounds rounds. The last round
+// gets generations generations per map. Returns the best trial.
+func halve(trials []*trial, ms []maps.Map, rounds, generations int, seed int64, seeds, workers int) *trial {
+	for round := 0; ; round++ {
+		g := generations >> uint(rounds-round)
+		if g < 1 {
+			g = 1
+		}
+		for i, t := range trials {
+			t.score = 0
+			for j := 0; j < seeds; j++ {
+				// Every trial gets the same seeds so they're only as
+				// different as their configs
+				res := t.solve(context.Background(), ms, solver.DeriveSeed(seed, j), g, workers)
+				t.score += float64(res.score) / float64(seeds)
+			}
+			fmt.Fprintf(debug.Out, "Round %d trial %d scored %.1f in %d generations: %s\n", round, i, t.score, g, t.config)
+		}
+		sort.SliceStable(trials, func(i, j int) bool {
+			return trials[i].score > trials[j].score
+		})
+		if round >= rounds || len(trials) == 1 {
+			return trials[0]
+		}
+		trials = trials[:(len(trials)+1)/2]
+	}
+}
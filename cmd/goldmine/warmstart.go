@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/inlined/goldmine/pkg/debug"
+	"github.com/inlined/goldmine/pkg/maps"
+)
+
+// readWarmStart reads the paths in an earlier run's answer file, one
+// list per map in mapText: its answer followed by any --alternatives
+// written with it.
+func readWarmStart(path string, mapText io.Reader) [][]maps.Path {
+	if path == "" {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		panic(fmt.Sprintf("Unexpected error opening %s: %s", path, err))
+	}
+	defer f.Close()
+
+	var paths [][]maps.Path
+	r := maps.NewAnswerReader(mapText, f)
+	var a maps.Answer
+	for a, err = r.Next(); err == nil; a, err = r.Next() {
+		paths = append(paths, []maps.Path{a.Path})
+	}
+	if err != io.EOF {
+		panic(fmt.Sprintf("Unexpected error reading %s: %s", path, err))
+	}
+
+	// Alternatives are comments that name their map
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		panic(fmt.Sprintf("Unexpected error reading %s: %s", path, err))
+	}
+	lines := bufio.NewScanner(f)
+	for lines.Scan() {
+		var i, rank, score int
+		var alt string
+		n, _ := fmt.Sscanf(lines.Text(), maps.AnswerComment+" map %d alternative %d score=%d %s", &i, &rank, &score, &alt)
+		if n == 4 && i >= 0 && i < len(paths) {
+			paths[i] = append(paths[i], maps.ParsePath(alt))
+		}
+	}
+	if err := lines.Err(); err != nil {
+		panic(fmt.Sprintf("Unexpected error reading %s: %s", path, err))
+	}
+	return paths
+}
+
+// validWarmStart drops the paths for map i that aren't legal answers.
+// Paths that stop early still score, so they are padded to full length
+// for solvers that encode every step.
+func validWarmStart(i int, m maps.Map, paths []maps.Path) []maps.Path {
+	var valid []maps.Path
+	for _, p := range paths {
+		err := p.Validate(m)
+		if pe, ok := err.(*maps.PathError); ok && pe.Reason != maps.TooShort {
+			fmt.Fprintf(debug.Out, "Map %d ignoring warm start path %s: %s\n", i, p, err)
+			continue
+		}
+		p = p.Copy()
+		p.Pad(m)
+		valid = append(valid, p)
+	}
+	return valid
+}
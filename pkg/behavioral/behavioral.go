@@ -0,0 +1,76 @@
+package behavioral
+
+import (
+	"fmt"
+
+	"github.com/inlined/genetics"
+	"github.com/inlined/goldmine/pkg/maps"
+	"github.com/inlined/goldmine/pkg/solver"
+)
+
+func init() {
+	solver.DefaultRegistry.Register(solver.Descriptor{
+		Name:        "behavioral",
+		Description: "evolves the weights a step by step search uses to choose which paths to explore",
+		Options: []solver.Option{{
+			Name:        "beam",
+			Type:        solver.IntOption,
+			Default:     "64",
+			Description: "paths explored further each step, the ones the strategy prefers first",
+		}},
+		New: func(i solver.Input, opts solver.Options) solver.Solver {
+			return &Solver{Genetic: solver.Genetic{Input: i}, beam: opts.Int("beam")}
+		},
+	})
+}
+
+// Solver evolves Strategies and mines the map with each of them
+type Solver struct {
+	solver.Genetic
+	beam  int
+	miner *miner
+}
+
+// Init creates a random population of Strategies, starting with ones
+// that lean the way each path of Input.WarmStart went
+func (s *Solver) Init(popSize int) error {
+	if s.beam < 1 {
+		return fmt.Errorf("behavioral.Solver.Init(): beam must be positive, got %d", s.beam)
+	}
+	species := genetics.NewSpecies(numGenes, maxGene)
+	s.miner = newMiner(s.Map, s.beam)
+	population := make([]genetics.Chromosome, popSize)
+	for i := 0; i < popSize; i++ {
+		population[i], _ = species.NewRand(s.Rand)
+	}
+	for i, p := range s.WarmStart {
+		if i == popSize {
+			break
+		}
+		encode(population[i], s.miner.trace(p))
+	}
+
+	genome := solver.Genome{
+		Species: species,
+		Path:    s.Path,
+		Encode: func(c genetics.Chromosome, p maps.Path) {
+			encode(c, s.miner.trace(p))
+		},
+	}
+	s.Start(genome, population, solver.InitEvent{
+		PointsOfInterest: len(s.Map.PointsOfInterest),
+		Genes:            species.NumGenes,
+		PopulationSize:   popSize,
+	})
+	return nil
+}
+
+// Path mines the map with the Strategy c encodes
+func (s *Solver) Path(c genetics.Chromosome) maps.Path {
+	return s.miner.mine(decode(c))
+}
+
+// Strategy decodes the Strategy in a chromosome
+func (s *Solver) Strategy(c genetics.Chromosome) Strategy {
+	return decode(c)
+}
@@ -0,0 +1,90 @@
+package behavioral_test
+
+import (
+	mrand "math/rand"
+	"strings"
+	"testing"
+
+	"github.com/inlined/genetics"
+	"github.com/inlined/goldmine/pkg/behavioral"
+	"github.com/inlined/goldmine/pkg/maps"
+	"github.com/inlined/goldmine/pkg/solver"
+	"github.com/inlined/rand"
+)
+
+func TestTraitScorers(t *testing.T) {
+	for _, test := range []struct {
+		tag    string
+		op     behavioral.TraitScorer
+		trait  int
+		weight int
+		want   float64
+	}{
+		{tag: "geometric", op: behavioral.Geometric, trait: 3, weight: 2, want: 6},
+		{tag: "geometric negative", op: behavioral.Geometric, trait: 3, weight: -2, want: -6},
+		{tag: "geometric clamped", op: behavioral.Geometric, trait: 3, weight: 9, want: 12},
+		{tag: "exponential", op: behavioral.Exponential, trait: 3, weight: 2, want: 9},
+		{tag: "exponential negative trait", op: behavioral.Exponential, trait: -3, weight: 3, want: -27},
+		{tag: "exponential both negative", op: behavioral.Exponential, trait: -3, weight: -1, want: 3},
+		{tag: "exponential clamped", op: behavioral.Exponential, trait: 2, weight: 7, want: 16},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			if got := test.op.ScoreTrait(test.trait, test.weight); got != test.want {
+				t.Errorf("ScoreTrait(%d, %d) = %g; want %g", test.trait, test.weight, got, test.want)
+			}
+		})
+	}
+}
+
+func TestWarmStart(t *testing.T) {
+	r := maps.NewReader(strings.NewReader(`=1,9,4
+		9...s...1`))
+	m, err := r.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	warm := maps.ParsePath("llll")
+	s, err := solver.DefaultRegistry.New("behavioral", solver.Input{
+		Map:       m,
+		Rand:      rand.New(),
+		WarmStart: []maps.Path{warm},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Init(5); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first of the population should head the same way
+	c := genetics.Chromosome{Genes: s.Checkpoint().Population[0]}
+	if st := s.(*behavioral.Solver).Strategy(c); st.Horizontal <= 0 {
+		t.Errorf("warm started Strategy %+v doesn't prefer ending left of the start", st)
+	}
+	if p := s.Path(c); p.Score(m) != warm.Score(m) {
+		t.Errorf("warm started Strategy mined %s scoring %d; want %d", p, p.Score(m), warm.Score(m))
+	}
+}
+
+// BenchmarkPath mines a contest-sized map once per iteration
+func BenchmarkPath(b *testing.B) {
+	m, err := maps.Generate(mrand.New(mrand.NewSource(1)), maps.DefaultGenerateOptions)
+	if err != nil {
+		b.Fatal(err)
+	}
+	s, err := solver.DefaultRegistry.New("behavioral", solver.Input{Map: m, Rand: rand.New()})
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := s.Init(16); err != nil {
+		b.Fatal(err)
+	}
+	var population []genetics.Chromosome
+	for _, genes := range s.Checkpoint().Population {
+		population = append(population, genetics.Chromosome{Genes: genes})
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Path(population[i%len(population)])
+	}
+}
@@ -0,0 +1,208 @@
+package behavioral
+
+import (
+	"sort"
+
+	"github.com/inlined/goldmine/pkg/maps"
+)
+
+// node is one step of a path in the search tree
+type node struct {
+	v      maps.Vertex
+	parent int
+	d      maps.Direction
+	traits
+}
+
+// candidate is a path that reaches a cell this step, waiting to find out
+// whether it's the one kept there
+type candidate struct {
+	node
+	// from is the index into the frontier of the path it extends
+	from  int
+	score float64
+}
+
+// miner makes one attempt to solve a map with a Strategy. Each step it
+// extends every path in the frontier in every direction. Of the paths
+// that reach the same position only the one the Strategy scores highest
+// is kept, and only the beam highest of those go on to the next step. A
+// miner reuses its memory between calls and is not safe for concurrent
+// use.
+type miner struct {
+	m    maps.Map
+	beam int
+	// nodes holds every path kept so far; frontier holds the indexes of
+	// the paths being extended
+	nodes    []node
+	frontier []int
+	// seen holds a bitset of the cells each path in frontier has visited,
+	// words at a time
+	seen, nextSeen []uint64
+	words          int
+	candidates     []candidate
+	// order holds the indexes of the candidates that are kept, the ones
+	// the Strategy prefers first
+	order []int
+	// bestAt is the index into candidates of the path kept at each cell,
+	// or -1
+	bestAt []int
+}
+
+func newMiner(m maps.Map, beam int) *miner {
+	mi := &miner{
+		m:      m,
+		beam:   beam,
+		words:  (m.Rows()*m.Cols() + 63) / 64,
+		bestAt: make([]int, m.Rows()*m.Cols()),
+	}
+	for i := range mi.bestAt {
+		mi.bestAt[i] = -1
+	}
+	return mi
+}
+
+// cell is the index of v in bestAt and in each bitset of seen
+func (mi *miner) cell(v maps.Vertex) int {
+	return v.Row*mi.m.Cols() + v.Col
+}
+
+// push extends the path ending at nodes[i], which has visited the cells
+// in seen, by d and returns the new node
+func (mi *miner) push(i int, seen []uint64, d maps.Direction) node {
+	from := mi.nodes[i]
+	n := node{v: from.v.Move(d), parent: i, d: d, traits: from.traits}
+	start := mi.m.PointsOfInterest[0]
+	n.horizontal = start.Col - n.v.Col
+	n.vertical = start.Row - n.v.Row
+	cell := mi.cell(n.v)
+	switch x := mi.m.At(n.v); {
+	case seen[cell/64]&(1<<uint(cell%64)) != 0:
+		n.retraces++
+	case x == maps.Pickaxe:
+		n.pickaxes++
+	case x >= '1' && x <= '9':
+		n.value += int(x-'0') << uint(n.pickaxes)
+	default:
+		n.noops++
+	}
+	return n
+}
+
+// root starts the search with a path that hasn't left the start
+func (mi *miner) root() {
+	start := mi.m.PointsOfInterest[0]
+	mi.nodes = append(mi.nodes[:0], node{v: start, parent: -1})
+	mi.frontier = append(mi.frontier[:0], 0)
+	mi.seen = append(mi.seen[:0], make([]uint64, mi.words)...)
+	cell := mi.cell(start)
+	mi.seen[cell/64] |= 1 << uint(cell%64)
+}
+
+// Len, Less, and Swap sort order by how much the Strategy prefers each
+// candidate, keeping ties in the order they were found
+func (mi *miner) Len() int {
+	return len(mi.order)
+}
+
+func (mi *miner) Less(i, j int) bool {
+	a, b := mi.order[i], mi.order[j]
+	if mi.candidates[a].score != mi.candidates[b].score {
+		return mi.candidates[a].score > mi.candidates[b].score
+	}
+	return a < b
+}
+
+func (mi *miner) Swap(i, j int) {
+	mi.order[i], mi.order[j] = mi.order[j], mi.order[i]
+}
+
+// trace walks p, stopping early if it leaves the map, and returns the
+// traits of where it ends
+func (mi *miner) trace(p maps.Path) traits {
+	mi.root()
+	for _, d := range p {
+		if !mi.m.CanBeAt(mi.nodes[len(mi.nodes)-1].v.Move(d)) {
+			break
+		}
+		n := mi.push(len(mi.nodes)-1, mi.seen, d)
+		mi.nodes = append(mi.nodes, n)
+		cell := mi.cell(n.v)
+		mi.seen[cell/64] |= 1 << uint(cell%64)
+	}
+	return mi.nodes[len(mi.nodes)-1].traits
+}
+
+// mine finds a path that is StepsAllowed long, or stops early if the
+// start is boxed in
+func (mi *miner) mine(s Strategy) maps.Path {
+	m := mi.m
+	mi.root()
+	for step := 0; step < m.StepsAllowed; step++ {
+		// The last step only needs to earn the most points
+		if step == m.StepsAllowed-1 {
+			s = Greedy
+		}
+		mi.candidates = mi.candidates[:0]
+		for f, i := range mi.frontier {
+			seen := mi.seen[f*mi.words : (f+1)*mi.words]
+			for _, d := range []maps.Direction{maps.Up, maps.Down, maps.Left, maps.Right} {
+				v := mi.nodes[i].v.Move(d)
+				if !m.CanBeAt(v) {
+					continue
+				}
+				c := candidate{node: mi.push(i, seen, d), from: f}
+				c.score = s.score(c.traits)
+				cell := mi.cell(v)
+				if at := mi.bestAt[cell]; at == -1 {
+					mi.bestAt[cell] = len(mi.candidates)
+					mi.candidates = append(mi.candidates, c)
+				} else if c.score > mi.candidates[at].score {
+					mi.candidates[at] = c
+				}
+			}
+		}
+		if len(mi.candidates) == 0 {
+			// Boxed in
+			break
+		}
+		for _, c := range mi.candidates {
+			mi.bestAt[mi.cell(c.v)] = -1
+		}
+		mi.order = mi.order[:0]
+		for i := range mi.candidates {
+			mi.order = append(mi.order, i)
+		}
+		if len(mi.order) > mi.beam {
+			sort.Sort(mi)
+			mi.order = mi.order[:mi.beam]
+		}
+
+		mi.frontier = mi.frontier[:0]
+		mi.nextSeen = mi.nextSeen[:0]
+		for _, x := range mi.order {
+			c := &mi.candidates[x]
+			mi.frontier = append(mi.frontier, len(mi.nodes))
+			mi.nodes = append(mi.nodes, c.node)
+			mi.nextSeen = append(mi.nextSeen, mi.seen[c.from*mi.words:(c.from+1)*mi.words]...)
+			cell := mi.cell(c.v)
+			mi.nextSeen[len(mi.nextSeen)-mi.words+cell/64] |= 1 << uint(cell%64)
+		}
+		mi.seen, mi.nextSeen = mi.nextSeen, mi.seen
+	}
+
+	best := mi.frontier[0]
+	for _, i := range mi.frontier {
+		if mi.nodes[i].value > mi.nodes[best].value {
+			best = i
+		}
+	}
+	p := make(maps.Path, 0, m.StepsAllowed)
+	for i := best; mi.nodes[i].parent != -1; i = mi.nodes[i].parent {
+		p.Append(mi.nodes[i].d)
+	}
+	for l, r := 0, len(p)-1; l < r; l, r = l+1, r-1 {
+		p[l], p[r] = p[r], p[l]
+	}
+	return p
+}
@@ -1,5 +1,6 @@
 // Package behavioral implements a solution to Goldmine inspired by A*. No attempts to reduce
-// the graph are encoded in this solution. Instead, a fully connected matrix
-// encodes all positions on the board. Solutions are explored based on preferences
-// encoded in genes of a chromosome.
+// the graph are encoded in this solution. Instead, every position on the board may be
+// reached at each step, only the path there that a strategy prefers is explored further,
+// and only the paths it prefers most are kept at all.
+// Strategies are weights for the traits of a path encoded in genes of a chromosome.
 package behavioral
@@ -0,0 +1,177 @@
+package behavioral
+
+import (
+	"math"
+
+	"github.com/inlined/genetics"
+)
+
+// maxWeight is the largest magnitude of any weight in a Strategy
+const maxWeight = 4
+
+// TraitScorer turns a trait of a path and the weight a Strategy gives it
+// into a score
+type TraitScorer interface {
+	ScoreTrait(trait, weight int) float64
+}
+
+type geometric struct{}
+type exponential struct{}
+
+var (
+	// Geometric scores a trait as trait * weight
+	Geometric TraitScorer = geometric{}
+
+	// Exponential scores a trait as trait ^ |weight|, negated if
+	// exactly one of the trait and weight is negative
+	Exponential TraitScorer = exponential{}
+)
+
+func clamp(weight int) int {
+	if weight > maxWeight {
+		return maxWeight
+	}
+	if weight < -maxWeight {
+		return -maxWeight
+	}
+	return weight
+}
+
+func (geometric) ScoreTrait(trait, weight int) float64 {
+	return float64(trait) * float64(clamp(weight))
+}
+
+func (exponential) ScoreTrait(trait, weight int) float64 {
+	weight = clamp(weight)
+	// Weights are small, so multiplying is much quicker than math.Pow
+	score := 1.0
+	for i := 0; i < abs(weight); i++ {
+		score *= math.Abs(float64(trait))
+	}
+	if (trait < 0) != (weight < 0) {
+		return -score
+	}
+	return score
+}
+
+// Strategy decides which of the paths that reach a position is worth
+// exploring further. Each trait of a path is scored by Op with its weight
+// and the path with the highest total wins.
+type Strategy struct {
+	Op TraitScorer
+
+	// Pickaxe and Value weigh the pickaxes and points collected so far
+	Pickaxe int
+	Value   int
+
+	// Distance weighs how far the path ends from the start. It may be
+	// negative to prefer staying close.
+	Distance int
+
+	// Retrace and Noop are penalties for steps onto positions the path
+	// has already visited and onto empty positions
+	Retrace int
+	Noop    int
+
+	// Horizontal and Vertical prefer ending left of or above the start,
+	// or right of or below it when negative
+	Horizontal int
+	Vertical   int
+}
+
+// Greedy only cares about points
+var Greedy = Strategy{Op: Geometric, Value: 1}
+
+// traits describe a path for a Strategy to score
+type traits struct {
+	value, pickaxes      int
+	retraces, noops      int
+	horizontal, vertical int
+}
+
+// score totals the weighted traits of a path
+func (s Strategy) score(t traits) float64 {
+	distance := abs(t.horizontal) + abs(t.vertical)
+	return s.Op.ScoreTrait(t.value, s.Value) +
+		s.Op.ScoreTrait(t.pickaxes, s.Pickaxe) +
+		s.Op.ScoreTrait(distance, s.Distance) +
+		s.Op.ScoreTrait(t.horizontal, s.Horizontal) +
+		s.Op.ScoreTrait(t.vertical, s.Vertical) -
+		s.Op.ScoreTrait(t.retraces, s.Retrace) -
+		s.Op.ScoreTrait(t.noops, s.Noop)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// Chromosome layout. Every gene is between 0 and maxGene so that small
+// changes to a gene make small changes to behavior. Signed weights are
+// offset so that the middle gene is zero.
+const (
+	opGene = iota
+	pickaxeGene
+	valueGene
+	distanceGene
+	retraceGene
+	noopGene
+	horizontalGene
+	verticalGene
+	numGenes
+
+	maxGene = 2 * maxWeight
+)
+
+// decode reads the Strategy a chromosome encodes
+func decode(c genetics.Chromosome) Strategy {
+	g := func(i int) int {
+		return int(c.Genes[i])
+	}
+	op := Geometric
+	if g(opGene)%2 == 1 {
+		op = Exponential
+	}
+	return Strategy{
+		Op:         op,
+		Pickaxe:    g(pickaxeGene) / 2,
+		Value:      g(valueGene) / 2,
+		Distance:   g(distanceGene) - maxWeight,
+		Retrace:    g(retraceGene) / 2,
+		Noop:       g(noopGene) / 2,
+		Horizontal: g(horizontalGene) - maxWeight,
+		Vertical:   g(verticalGene) - maxWeight,
+	}
+}
+
+// encode overwrites c with a Geometric Strategy that favors the traits of
+// a path it should lean toward: its points, its pickaxes if it found any,
+// avoiding what it avoided, and ending on the same side of the start.
+func encode(c genetics.Chromosome, t traits) {
+	sign := func(x int) genetics.Gene {
+		switch {
+		case x < 0:
+			return maxWeight - maxWeight/2
+		case x > 0:
+			return maxWeight + maxWeight/2
+		}
+		return maxWeight
+	}
+	// Unsigned weights are doubled in their genes
+	some := func(want bool) genetics.Gene {
+		if want {
+			return maxWeight
+		}
+		return 0
+	}
+	c.Genes[opGene] = 0
+	c.Genes[valueGene] = maxGene
+	c.Genes[pickaxeGene] = some(t.pickaxes > 0)
+	c.Genes[distanceGene] = sign(abs(t.horizontal) + abs(t.vertical))
+	c.Genes[retraceGene] = some(t.retraces == 0)
+	c.Genes[noopGene] = some(t.noops == 0)
+	c.Genes[horizontalGene] = sign(t.horizontal)
+	c.Genes[verticalGene] = sign(t.vertical)
+}
@@ -8,24 +8,28 @@ import (
 	"github.com/inlined/goldmine/pkg/solver"
 )
 
-const (
-	// How many extra genes to allow for walking into disallowed spaces
-	genomePaddingRatio = 1.2
-)
-
 func init() {
-	solver.RegisterSolverFlag("bruteforce", func(i solver.Input) solver.Solver {
-		return &Solver{Input: i}
+	solver.DefaultRegistry.Register(solver.Descriptor{
+		Name:        "bruteforce",
+		Description: "evolves a direction for every step",
+		Options: []solver.Option{{
+			Name:        "padding",
+			Type:        solver.FloatOption,
+			Default:     "1.2",
+			Description: "genes per allowed step, leaving extras for walking into disallowed spaces",
+		}},
+		New: func(i solver.Input, opts solver.Options) solver.Solver {
+			return &Solver{Genetic: solver.Genetic{Input: i}, paddingRatio: opts.Float("padding")}
+		},
 	})
 }
 
 // Solver solves a Goldmine map with brute force
 type Solver struct {
-	solver.Input
-	species    *genetics.Species
-	population []genetics.Chromosome
-	best       genetics.Chromosome
-	score      int
+	solver.Genetic
+	// How many genes to allow per step, for walking into disallowed spaces
+	paddingRatio float64
+	complete     *maps.Completer
 }
 
 func toDir(g genetics.Gene) maps.Direction {
@@ -43,12 +47,34 @@ func toDir(g genetics.Gene) maps.Direction {
 	}
 }
 
-// Path transaltes a Chromosome into a valid Path
+func toGene(d maps.Direction) genetics.Gene {
+	switch d {
+	case maps.Up:
+		return 0
+	case maps.Down:
+		return 1
+	case maps.Left:
+		return 2
+	case maps.Right:
+		return 3
+	default:
+		panic(fmt.Sprintf("Unexpected direction %c", d))
+	}
+}
+
+// Path transaltes a Chromosome into a valid Path. Unlike the Solver's
+// own translations it has its own Completer, so it is safe to call
+// concurrently.
 func (s Solver) Path(c genetics.Chromosome) maps.Path {
+	return s.path(c, maps.NewCompleter(s.Map))
+}
+
+// path translates c, extending it with complete
+func (s Solver) path(c genetics.Chromosome, complete *maps.Completer) maps.Path {
 	p := maps.Path(make([]maps.Direction, 0, s.Map.StepsAllowed))
 	v := s.Map.PointsOfInterest[0]
 
-	for i := 0; i < c.Species.NumGenes && p.Len() <= s.Map.StepsAllowed; i++ {
+	for i := 0; i < len(c.Genes) && p.Len() < s.Map.StepsAllowed; i++ {
 		d := toDir(c.Genes[i])
 		v2 := v.Move(d)
 		if !s.Map.CanBeAt(v2) {
@@ -59,46 +85,46 @@ func (s Solver) Path(c genetics.Chromosome) maps.Path {
 	}
 
 	// In case we run out of valid genes before StepsAllowed
-	p.Pad(s.Map)
-	return p
+	return complete.Complete(p)
+}
+
+// encode overwrites the first genes of c with the directions of p
+func (s *Solver) encode(c genetics.Chromosome, p maps.Path) {
+	for i := 0; i < len(p) && i < len(c.Genes); i++ {
+		c.Genes[i] = toGene(p[i])
+	}
 }
 
 // Init creates all necessary private variables
 func (s *Solver) Init(popSize int) error {
-	numGenes := float32(s.Map.StepsAllowed) * genomePaddingRatio
-	s.species = genetics.NewSpecies(int(numGenes), 3)
-	s.population = make([]genetics.Chromosome, popSize)
+	if s.paddingRatio < 1 {
+		return fmt.Errorf("bruteforce.Solver.Init(): padding must be at least 1, got %g", s.paddingRatio)
+	}
+	numGenes := float64(s.Map.StepsAllowed) * s.paddingRatio
+	species := genetics.NewSpecies(int(numGenes), 3)
+	s.complete = maps.NewCompleter(s.Map)
+	population := make([]genetics.Chromosome, popSize)
 	for i := 0; i < popSize; i++ {
-		s.population[i], _ = s.species.NewRand(s.Rand)
+		population[i], _ = species.NewRand(s.Rand)
 	}
-
-	return nil
-}
-
-// Step iterates through count generations of evolution,
-// updating the population, score, and best path
-func (s *Solver) Step(count int) {
-	fitness := make([]genetics.Fitness, len(s.population))
-	for i := 0; i < count; i++ {
-		for n, c := range s.population {
-			p := s.Path(c)
-			score := p.Score(s.Map)
-			fitness[n] = genetics.Fitness(score)
-			if score > s.score {
-				s.score = score
-				s.best = c
-			}
+	for i, p := range s.WarmStart {
+		if i == popSize {
+			break
 		}
-		s.Evolver.Evolve(s.Rand, s.population, fitness)
+		s.encode(population[i], p)
 	}
-}
 
-// Score accesses the current best score
-func (s *Solver) Score() int {
-	return s.score
-}
-
-// Best returns the winning chromosome.
-func (s *Solver) Best() genetics.Chromosome {
-	return s.best
+	genome := solver.Genome{
+		Species: species,
+		Path: func(c genetics.Chromosome) maps.Path {
+			return s.path(c, s.complete)
+		},
+		Encode: s.encode,
+	}
+	s.Start(genome, population, solver.InitEvent{
+		PointsOfInterest: len(s.Map.PointsOfInterest),
+		Genes:            species.NumGenes,
+		PopulationSize:   popSize,
+	})
+	return nil
 }
@@ -1,2 +1,30 @@
 package bruteforce_test
 
+import (
+	"strings"
+	"testing"
+
+	"github.com/inlined/genetics"
+	_ "github.com/inlined/goldmine/pkg/bruteforce"
+	"github.com/inlined/goldmine/pkg/maps"
+	"github.com/inlined/goldmine/pkg/solver"
+	"github.com/inlined/rand"
+)
+
+func TestPathBeforeInit(t *testing.T) {
+	r := maps.NewReader(strings.NewReader(`=1,5,4
+		s.9.1`))
+	m, err := r.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := solver.DefaultRegistry.New("bruteforce", solver.Input{Map: m, Rand: rand.New()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Right, then completed to the 9 and the 1
+	p := s.Path(genetics.Chromosome{Genes: []genetics.Gene{3}})
+	if got, want := p.String(), "rrrr"; got != want {
+		t.Errorf("Path() = %s; want %s", got, want)
+	}
+}
@@ -0,0 +1,948 @@
+package exact
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/inlined/genetics"
+	"github.com/inlined/goldmine/pkg/maps"
+	"github.com/inlined/goldmine/pkg/solver"
+)
+
+const (
+	// How many nodes of the search tree to expand for each Step count
+	nodesPerStep = 1000
+
+	// Upper limit on the transposition table so large maps don't exhaust memory
+	maxMemo = 1 << 22
+
+	// Marks a cell that isn't a point of interest
+	noPoi = -1
+
+	// How many pickaxes are in each neighbourhood remembered uses
+	neighbours = 8
+
+	// How many moves back harvests remembers
+	memory = 4
+)
+
+func init() {
+	solver.DefaultRegistry.Register(solver.Descriptor{
+		Name:        "exact",
+		Description: "branch and bound search that can prove a path optimal",
+		New: func(i solver.Input, _ solver.Options) solver.Solver {
+			return &Solver{Input: i}
+		},
+	})
+}
+
+// frame is one level of the depth first search. Each frame knows
+// how to undo the changes made when entering its vertex.
+type frame struct {
+	v    maps.Vertex
+	prev maps.Vertex
+
+	// The order in which to try directions and how many have been tried
+	order [4]maps.Direction
+	tried int
+
+	// poi is the point of interest collected when entering v or noPoi
+	poi      int
+	oldScore int
+}
+
+// reach is a point of interest and how far it is from some cell
+type reach struct {
+	poi  int
+	dist int
+}
+
+// Solver walks every path on a map, pruning branches that cannot beat
+// the best path found so far.
+type Solver struct {
+	solver.Input
+	solver.Observers
+	species *genetics.Species
+
+	// poiAt maps a cell index to its index in Map.PointsOfInterest or noPoi
+	poiAt []int
+	// near lists the points of interest reachable from each cell, closest first
+	near [][]reach
+	// tour holds, for each cell, the fewest steps from there to collect
+	// each number of pickaxes according to tours
+	tour [][]int
+	// harvest holds, for each cell, the most digits according to harvests
+	// that can be collected in each number of steps from there
+	harvest [][]int
+	// others lists the other pickaxes reachable from each pickaxe, closest
+	// first
+	others [][]reach
+	// reachable marks the pickaxes bound finds within reach with the
+	// number of the call that found them
+	reachable []int
+	calls     int
+	collected []bool
+	// memo holds the best score of a search node with each memoKey, and key
+	// is memoKey's buffer
+	memo map[string]int
+	key  []byte
+	// scratch space for bound
+	dists, axes, inner, counts, earliest, slots, reap []int
+
+	stack    []frame
+	path     maps.Path
+	score    int
+	pickaxes uint
+
+	best      genetics.Chromosome
+	bestScore int
+	optimal   bool
+	nodes     int
+	// generation counts calls to expand, each nodesPerStep nodes
+	generation int
+	found      int
+	elapsed    time.Duration
+	// rootBound is the most any path can score according to bound
+	rootBound int
+	// hall only sees paths the search visits, so alternatives to an
+	// optimal path may have been pruned
+	hall solver.HallOfFame
+}
+
+func toGene(d maps.Direction) genetics.Gene {
+	switch d {
+	case maps.Up:
+		return 0
+	case maps.Down:
+		return 1
+	case maps.Left:
+		return 2
+	case maps.Right:
+		return 3
+	default:
+		panic(fmt.Sprintf("Unexpected direction %c", d))
+	}
+}
+
+func toDir(g genetics.Gene) maps.Direction {
+	switch g {
+	case 0:
+		return maps.Up
+	case 1:
+		return maps.Down
+	case 2:
+		return maps.Left
+	case 3:
+		return maps.Right
+	default:
+		panic(fmt.Sprintf("Unexpected gene %d", g))
+	}
+}
+
+// Init precomputes distances and prepares the search
+func (s *Solver) Init(popSize int) error {
+	m := s.Map
+	s.species = genetics.NewSpecies(m.StepsAllowed, 3)
+	s.poiAt = make([]int, m.Rows()*m.Cols())
+	for i := range s.poiAt {
+		s.poiAt[i] = noPoi
+	}
+	dist := make([][]int, len(m.PointsOfInterest))
+	for x, v := range m.PointsOfInterest {
+		s.poiAt[v.Row*m.Cols()+v.Col] = x
+		dist[x] = m.Distances(v)
+	}
+	s.near = make([][]reach, len(s.poiAt))
+	// Sort each list by counting how many are at each distance
+	at := make([]int, m.StepsAllowed+2)
+	for cell := range s.near {
+		for d := range at {
+			at[d] = 0
+		}
+		for x := range m.PointsOfInterest {
+			if d := dist[x][cell]; d >= 0 && d <= m.StepsAllowed {
+				at[d+1]++
+			}
+		}
+		for d := 1; d < len(at); d++ {
+			at[d] += at[d-1]
+		}
+		s.near[cell] = make([]reach, at[len(at)-1])
+		for x := range m.PointsOfInterest {
+			if d := dist[x][cell]; d >= 0 && d <= m.StepsAllowed {
+				s.near[cell][at[d]] = reach{poi: x, dist: d}
+				at[d]++
+			}
+		}
+	}
+
+	pickaxes := 0
+	s.others = make([][]reach, len(m.PointsOfInterest))
+	for x, v := range m.PointsOfInterest {
+		if m.At(v) != maps.Pickaxe {
+			continue
+		}
+		pickaxes++
+		for _, r := range s.near[v.Row*m.Cols()+v.Col] {
+			if r.poi != x && m.At(m.PointsOfInterest[r.poi]) == maps.Pickaxe {
+				s.others[x] = append(s.others[x], r)
+			}
+		}
+	}
+	s.reachable = make([]int, len(m.PointsOfInterest))
+	s.tour = s.tours(dist)
+	s.harvest = s.harvests(dist[0])
+	s.dists = make([]int, 0, pickaxes)
+	s.inner = make([]int, 0, pickaxes)
+	s.counts = make([]int, 2*m.StepsAllowed+3)
+	s.earliest = make([]int, 0, pickaxes+1)
+	s.slots = make([]int, pickaxes+1)
+	s.axes = make([]int, 0, pickaxes)
+	s.reap = make([]int, 0, pickaxes+1)
+
+	s.collected = make([]bool, len(m.PointsOfInterest))
+	// The start is never worth anything
+	s.collected[0] = true
+	s.memo = make(map[string]int)
+
+	s.path = make(maps.Path, 0, m.StepsAllowed)
+	s.stack = []frame{s.newFrame(m.PointsOfInterest[0], maps.InvalidVertex, noPoi, 0)}
+	s.rootBound = s.bound(m.PointsOfInterest[0], m.StepsAllowed)
+	s.hall = solver.HallOfFame{Size: s.Alternatives}
+	s.record()
+	for _, p := range s.WarmStart {
+		s.Adopt(p)
+	}
+	s.Notify(solver.InitEvent{
+		PointsOfInterest: len(m.PointsOfInterest),
+		Genes:            s.species.NumGenes,
+	})
+	return nil
+}
+
+// tours finds, for every cell, a lower bound on the steps it takes to
+// collect each number of pickaxes from there, walking to the first of
+// them and on from there as remembered finds. Counts that can't be
+// collected within StepsAllowed are left off.
+func (s *Solver) tours(dist [][]int) [][]int {
+	m := s.Map
+	var axes []int
+	for x, v := range m.PointsOfInterest {
+		if m.At(v) == maps.Pickaxe {
+			axes = append(axes, x)
+		}
+	}
+	far := m.StepsAllowed + 1
+	// leg[a][b] is the distance from the ath to the bth pickaxe
+	leg := make([][]int, len(axes))
+	for a, x := range axes {
+		leg[a] = make([]int, len(axes))
+		for b, y := range axes {
+			v := m.PointsOfInterest[y]
+			leg[a][b] = dist[x][v.Row*m.Cols()+v.Col]
+			if leg[a][b] < 0 || leg[a][b] > far {
+				leg[a][b] = far
+			}
+		}
+	}
+	walks := remembered(leg, far)
+
+	tour := make([][]int, len(s.poiAt))
+	for cell := range tour {
+		tour[cell] = []int{0}
+		for j := 1; j < len(walks); j++ {
+			best := far
+			for a, x := range axes {
+				if d := dist[x][cell]; d >= 0 && d+walks[j][a] < best {
+					best = d + walks[j][a]
+				}
+			}
+			if best == far {
+				break
+			}
+			tour[cell] = append(tour[cell], best)
+		}
+	}
+	return tour
+}
+
+// remembered finds walks[j][a], a lower bound on the steps it takes to
+// collect j pickaxes starting on the ath, given the distances between
+// them. It relaxes the problem by giving each pickaxe a neighbourhood of
+// the few closest to it. A walk remembers the pickaxes it collected for as
+// long as it stays in their neighbourhoods, and may collect a pickaxe
+// again once it has forgotten it. Counts that take far steps or more are
+// left off.
+func remembered(leg [][]int, far int) [][]int {
+	// near[a] lists the ath pickaxe and its closest neighbours, and masks
+	// of the pickaxes a walk remembers on the ath are bits of near[a]
+	near := make([][]int, len(leg))
+	for a := range leg {
+		near[a] = []int{a}
+		for b := range leg {
+			if b != a && leg[a][b] < far {
+				near[a] = append(near[a], b)
+			}
+		}
+		others := near[a][1:]
+		sort.SliceStable(others, func(i, j int) bool {
+			return leg[a][others[i]] < leg[a][others[j]]
+		})
+		if len(near[a]) > neighbours {
+			near[a] = near[a][:neighbours]
+		}
+	}
+	// keep[a][b] translates a mask on the ath pickaxe into one on the bth,
+	// half of the bits at a time
+	const half = neighbours / 2
+	keep := make([][][2][1 << half]int, len(leg))
+	for a := range leg {
+		keep[a] = make([][2][1 << half]int, len(leg))
+		for b := range leg {
+			for i, x := range near[a] {
+				bit := 0
+				for k, y := range near[b] {
+					if x == y {
+						bit = 1 << uint(k)
+					}
+				}
+				for mask := 0; mask < 1<<half; mask++ {
+					if mask&(1<<uint(i%half)) != 0 {
+						keep[a][b][i/half][mask] |= bit
+					}
+				}
+			}
+		}
+	}
+
+	// from[a][mask] is the fewest steps to collect j pickaxes starting on
+	// the ath, remembering the pickaxes in mask
+	from := make([][]int, len(leg))
+	for a := range leg {
+		from[a] = make([]int, 1<<uint(len(near[a])))
+	}
+	walks := [][]int{make([]int, len(leg)), make([]int, len(leg))}
+	for j := 2; j <= len(leg); j++ {
+		nextFrom := make([][]int, len(leg))
+		walk := make([]int, len(leg))
+		more := false
+		for a := range leg {
+			nextFrom[a] = make([]int, len(from[a]))
+			for mask := range nextFrom[a] {
+				best := far
+				if mask&1 != 0 {
+					for b := range leg {
+						if b == a || leg[a][b] >= best {
+							continue
+						}
+						kept := keep[a][b][0][mask&(1<<half-1)] | keep[a][b][1][mask>>half]
+						if kept&1 != 0 {
+							// Remembered
+							continue
+						}
+						if c := leg[a][b] + from[b][kept|1]; c < best {
+							best = c
+						}
+					}
+				}
+				nextFrom[a][mask] = best
+			}
+			walk[a] = nextFrom[a][1]
+			more = more || walk[a] < far
+		}
+		if !more {
+			break
+		}
+		from = nextFrom
+		walks = append(walks, walk)
+	}
+	return walks
+}
+
+// harvests finds, for every cell, an upper bound on the value of the
+// digits a walk can collect in each number of steps from there, not
+// counting pickaxes. It relaxes the problem by letting a walk collect a
+// digit again once it's been more than memory steps since it was there.
+// Cells are only given as many steps as a path that walks to them from
+// the start, which is start steps away, has left.
+func (s *Solver) harvests(start []int) [][]int {
+	m := s.Map
+	dirs := [4]maps.Direction{maps.Up, maps.Down, maps.Left, maps.Right}
+	var step [4]maps.Vertex
+	for d, dir := range dirs {
+		step[d] = maps.Vertex{}.Move(dir)
+	}
+	// A state is the last few moves of a walk, the latest in the lowest two
+	// bits. States remembering k moves start at first[k].
+	first := make([]int, memory+2)
+	for k := 1; k < len(first); k++ {
+		first[k] = first[k-1] + 1<<uint(2*(k-1))
+	}
+	states := first[memory+1]
+	// next is the state after each move, and repeat is whether the move
+	// goes back to a cell the walk remembers
+	next := make([][4]int, states)
+	repeat := make([][4]bool, states)
+	for k := 0; k <= memory; k++ {
+		for code := 0; code < 1<<uint(2*k); code++ {
+			h := first[k] + code
+			for d := range dirs {
+				nk, nc := k+1, code<<2|d
+				if nk > memory {
+					nk, nc = memory, nc&(1<<uint(2*memory)-1)
+				}
+				next[h][d] = first[nk] + nc
+				// Walk back through the moves to each remembered cell
+				at := maps.Vertex{}
+				for i := 0; i < k; i++ {
+					back := step[code>>uint(2*i)&3]
+					at = maps.Vertex{Row: at.Row - back.Row, Col: at.Col - back.Col}
+					repeat[h][d] = repeat[h][d] || at == step[d]
+				}
+			}
+		}
+	}
+
+	cells := m.Rows() * m.Cols()
+	value := make([]int, cells)
+	move := make([][4]int, cells)
+	for cell := range move {
+		v := maps.Vertex{Row: cell / m.Cols(), Col: cell % m.Cols()}
+		if x := m.At(v); x >= '1' && x <= '9' {
+			value[cell] = int(x - '0')
+		}
+		for d, dir := range dirs {
+			move[cell][d] = -1
+			if v2 := v.Move(dir); m.CanBeAt(v) && m.CanBeAt(v2) {
+				move[cell][d] = v2.Row*m.Cols() + v2.Col
+			}
+		}
+	}
+
+	// best holds the most a walk of l steps collects from each cell and
+	// state, and then of l+1 steps in more
+	best := make([]int, cells*states)
+	more := make([]int, cells*states)
+	harvest := make([][]int, cells)
+	for cell := range harvest {
+		if start[cell] >= 0 && start[cell] <= m.StepsAllowed {
+			harvest[cell] = make([]int, 1, m.StepsAllowed-start[cell]+1)
+		}
+	}
+	for l := 1; l <= m.StepsAllowed; l++ {
+		for cell := range move {
+			if start[cell] < 0 || start[cell] > m.StepsAllowed-l {
+				continue
+			}
+			for h := 0; h < states; h++ {
+				top := 0
+				for d, to := range move[cell] {
+					if to < 0 {
+						continue
+					}
+					got := best[to*states+next[h][d]]
+					if !repeat[h][d] {
+						got += value[to]
+					}
+					if got > top {
+						top = got
+					}
+				}
+				more[cell*states+h] = top
+			}
+			harvest[cell] = append(harvest[cell], more[cell*states])
+		}
+		best, more = more, best
+	}
+	return harvest
+}
+
+// gain is the score earned by stepping onto cell with the current pickaxes
+func (s *Solver) gain(cell int) int {
+	poi := s.poiAt[cell]
+	if poi == noPoi || s.collected[poi] {
+		return 0
+	}
+	x := s.Map.At(s.Map.PointsOfInterest[poi])
+	if x == maps.Pickaxe {
+		// Not immediately valuable, but worth visiting before digits
+		return 1
+	}
+	return int(x-'0') << s.pickaxes
+}
+
+func (s *Solver) newFrame(v, prev maps.Vertex, poi, oldScore int) frame {
+	f := frame{
+		v:        v,
+		prev:     prev,
+		order:    [4]maps.Direction{maps.Up, maps.Down, maps.Left, maps.Right},
+		poi:      poi,
+		oldScore: oldScore,
+	}
+
+	// Explore the most immediately profitable directions first so that
+	// a strong incumbent is found early and prunes more of the tree.
+	var gains [4]int
+	for i, d := range f.order {
+		v2 := v.Move(d)
+		gains[i] = -1
+		if s.Map.CanBeAt(v2) {
+			gains[i] = s.gain(v2.Row*s.Map.Cols() + v2.Col)
+		}
+	}
+	for i := 1; i < len(f.order); i++ {
+		for j := i; j > 0 && gains[j] > gains[j-1]; j-- {
+			gains[j], gains[j-1] = gains[j-1], gains[j]
+			f.order[j], f.order[j-1] = f.order[j-1], f.order[j]
+		}
+	}
+	return f
+}
+
+// bound is an admissible estimate of how many more points can be earned
+// from v in the remaining steps. Each step collects at most one point of
+// interest within walking distance, the digits can't add up to more than
+// harvests allows, and a digit is only multiplied by the pickaxes that
+// could have been collected before it.
+func (s *Solver) bound(v maps.Vertex, remaining int) int {
+	var digits [10]int
+	dists, axes, inner := s.dists[:0], s.axes[:0], s.inner[:0]
+	s.calls++
+	for _, r := range s.near[v.Row*s.Map.Cols()+v.Col] {
+		if r.dist > remaining {
+			break
+		}
+		if s.collected[r.poi] {
+			continue
+		}
+		if c := s.Map.At(s.Map.PointsOfInterest[r.poi]); c == maps.Pickaxe {
+			dists = append(dists, r.dist)
+			inner = append(inner, r.poi)
+			s.reachable[r.poi] = s.calls
+		} else {
+			digits[c-'0']++
+		}
+	}
+	// The two smallest hops from one pickaxe within reach to another, and
+	// each pickaxe's two smallest hops in inner
+	end1, end2 := remaining+1, remaining+1
+	for i, x := range inner {
+		p := s.Map.PointsOfInterest[x]
+		axes = append(axes, p.Row*s.Map.Cols()+p.Col)
+		hop, hop2 := remaining+1, remaining+1
+		for _, r := range s.others[x] {
+			if r.dist > remaining {
+				break
+			}
+			if s.reachable[r.poi] != s.calls {
+				continue
+			}
+			if hop > remaining {
+				hop = r.dist
+				continue
+			}
+			hop2 = r.dist
+			break
+		}
+		inner[i] = hop + hop2
+		if hop < end1 {
+			end1, end2 = hop, end1
+		} else if hop < end2 {
+			end2 = hop
+		}
+	}
+	countingSort(inner, s.counts)
+
+	// earliest[j] is a lower bound on the steps needed to collect j pickaxes.
+	// The jth closest pickaxe can't be reached sooner than its distance.
+	// Walking between them passes each pickaxe in the middle of the walk on
+	// the way to and from two others, and the pickaxes at either end of it
+	// on the way to or from one other, and every step is counted twice.
+	earliest := append(s.earliest[:0], 0)
+	twice := 0
+	tour := s.tour[v.Row*s.Map.Cols()+v.Col]
+	for j := 1; j <= len(dists) && j < len(tour); j++ {
+		switch j {
+		case 1:
+		case 2:
+			twice = end1 + end2
+		default:
+			twice += inner[j-3]
+		}
+		e := earliest[j-1] + 1
+		if dists[j-1] > e {
+			e = dists[j-1]
+		}
+		if walk := dists[0] + (twice+1)/2; walk > e {
+			e = walk
+		}
+		if tour[j] > e {
+			e = tour[j]
+		}
+		if e > remaining {
+			break
+		}
+		earliest = append(earliest, e)
+	}
+
+	// reap[i] is the most the digits collected after the ith pickaxe can
+	// add up to, starting from wherever that pickaxe could be
+	reap := append(s.reap[:0], s.harvest[v.Row*s.Map.Cols()+v.Col][remaining])
+	for i := 1; i < len(earliest); i++ {
+		most := 0
+		for x, d := range dists {
+			if d < earliest[i] {
+				d = earliest[i]
+			}
+			if d > remaining {
+				break
+			}
+			if h := s.harvest[axes[x]][remaining-d]; h > most {
+				most = h
+			}
+		}
+		reap = append(reap, most)
+	}
+
+	best := 0
+	for j := range earliest {
+		// A digit collected between the ith and i+1th pickaxe is worth at
+		// most its value shifted by i. Spend the steps taken by pickaxes
+		// from the least valuable stretch of time.
+		n := s.slots[:j+1]
+		for i := 0; i < j; i++ {
+			n[i] = earliest[i+1] - earliest[i]
+		}
+		n[j] = remaining - earliest[j]
+		for i, left := 0, j; i <= j && left > 0; i++ {
+			take := n[i]
+			if take > left {
+				take = left
+			}
+			n[i] -= take
+			left -= take
+		}
+
+		// Each pickaxe doubles what the digits collected after it are
+		// worth, so add what the digits after each pickaxe are worth
+		// again to what all of them are worth. What the digits after the
+		// ith pickaxe add up to is limited by how many steps are left
+		// for them and by reap.
+		total := 0
+		after := 0
+		for i := j; i >= 0; i-- {
+			after += n[i]
+			sum := topDigits(&digits, after)
+			if reap[i] < sum {
+				sum = reap[i]
+			}
+			if i == 0 {
+				total += sum << s.pickaxes
+			} else {
+				total += sum << (s.pickaxes + uint(i) - 1)
+			}
+		}
+		if total > best {
+			best = total
+		}
+	}
+	return best
+}
+
+// countingSort sorts a, whose values are all less than len(counts), using
+// counts as scratch space. counts must be all zeros and is left that way.
+func countingSort(a, counts []int) {
+	for _, x := range a {
+		counts[x]++
+	}
+	i := 0
+	for x, n := range counts {
+		for ; n > 0; n-- {
+			a[i] = x
+			i++
+		}
+		counts[x] = 0
+	}
+}
+
+// topDigits sums the n most valuable digits
+func topDigits(digits *[10]int, n int) int {
+	sum := 0
+	for val := 9; val > 0 && n > 0; val-- {
+		take := digits[val]
+		if take > n {
+			take = n
+		}
+		sum += take * val
+		n -= take
+	}
+	return sum
+}
+
+// record saves the current path if it beats the best path so far or
+// belongs in the hall of fame
+func (s *Solver) record() {
+	improved := s.best.Species == nil || s.score > s.bestScore
+	if !improved && !s.hall.Accepts(s.score) {
+		return
+	}
+	p := s.path.Copy()
+	p.Pad(s.Map)
+	// Padding may wander onto something valuable
+	score := p.Score(s.Map)
+	s.hall.Offer(p, score)
+	if improved {
+		s.setBest(p, score)
+	}
+}
+
+// Adopt makes p, a warm start or a path found elsewhere, the best so far
+// if it beats it, so the search only looks for better ones
+func (s *Solver) Adopt(p maps.Path) {
+	p = p.Copy()
+	p.Pad(s.Map)
+	score := p.Score(s.Map)
+	s.hall.Offer(p, score)
+	if score > s.bestScore {
+		s.setBest(p, score)
+	}
+}
+
+// setBest saves p, a full length path, as the best path
+func (s *Solver) setBest(p maps.Path, score int) {
+	genes := make([]genetics.Gene, len(p))
+	for i, d := range p {
+		genes[i] = toGene(d)
+	}
+	s.best = genetics.Chromosome{Species: s.species, Genes: genes}
+	s.bestScore = score
+	s.found = s.generation
+	s.Notify(solver.ImprovementEvent{Generation: s.generation, Score: score, Path: p})
+}
+
+// memoKey identifies everything about a search node other than its score
+// that affects its future: where it is, how many steps and pickaxes it has,
+// and which of the points of interest it can still reach it has collected.
+// The key is only valid until the next call.
+func (s *Solver) memoKey(cell, remaining int) []byte {
+	k := append(s.key[:0], byte(cell), byte(cell>>8), byte(cell>>16), byte(remaining), byte(s.pickaxes))
+	var bits byte
+	for i, r := range s.near[cell] {
+		if r.dist > remaining {
+			break
+		}
+		bits <<= 1
+		if s.collected[r.poi] {
+			bits |= 1
+		}
+		if i%8 == 7 {
+			k = append(k, bits)
+			bits = 0
+		}
+	}
+	s.key = append(k, bits)
+	return s.key
+}
+
+// Step expands count thousand nodes of the search tree, updating the
+// score and best path. Once the search is exhausted Step does nothing.
+func (s *Solver) Step(count int) {
+	start := time.Now()
+	defer func() {
+		s.elapsed += time.Since(start)
+	}()
+	for i := 0; i < count && !s.optimal; i++ {
+		s.expand(nodesPerStep)
+		if s.Observed() {
+			s.Notify(solver.GenerationEvent{Generation: s.generation, Best: s.bestScore, Mean: float64(s.bestScore), Worst: s.bestScore})
+		}
+		s.generation++
+	}
+}
+
+// expand searches up to budget more nodes of the tree
+func (s *Solver) expand(budget int) {
+	m := s.Map
+	for budget > 0 && len(s.stack) != 0 {
+		top := &s.stack[len(s.stack)-1]
+		if top.tried == len(top.order) {
+			if top.poi != noPoi {
+				s.collected[top.poi] = false
+				if m.At(m.PointsOfInterest[top.poi]) == maps.Pickaxe {
+					s.pickaxes--
+				}
+			}
+			s.score = top.oldScore
+			s.stack = s.stack[:len(s.stack)-1]
+			if len(s.stack) != 0 {
+				s.path = s.path[:len(s.path)-1]
+			}
+			continue
+		}
+
+		d := top.order[top.tried]
+		top.tried++
+		v := top.v.Move(d)
+		if !m.CanBeAt(v) {
+			continue
+		}
+		// Walking A->B->A when B earned nothing is never better than
+		// staying at A, so don't bother.
+		if top.poi == noPoi && v == top.prev {
+			continue
+		}
+		budget--
+		s.nodes++
+
+		cell := v.Row*m.Cols() + v.Col
+		poi := s.poiAt[cell]
+		if poi != noPoi && s.collected[poi] {
+			poi = noPoi
+		}
+		oldScore := s.score
+		if poi != noPoi {
+			s.collected[poi] = true
+			if x := m.At(v); x == maps.Pickaxe {
+				s.pickaxes++
+			} else {
+				s.score += int(x-'0') << s.pickaxes
+			}
+		}
+		s.path.Append(d)
+		s.stack = append(s.stack, s.newFrame(v, top.v, poi, oldScore))
+		s.record()
+
+		// Decide whether the new frame is worth expanding
+		next := &s.stack[len(s.stack)-1]
+		remaining := m.StepsAllowed - len(s.path)
+		if remaining == 0 || s.score+s.bound(v, remaining) <= s.bestScore {
+			next.tried = len(next.order)
+			continue
+		}
+		// Paths that end up in the same place with the same steps and
+		// pickaxes left, and have collected the same points of interest
+		// that are still in reach, can do all the same things from there.
+		// Only the one with the most points needs to be searched.
+		key := s.memoKey(cell, remaining)
+		prior, ok := s.memo[string(key)]
+		if ok && prior >= s.score {
+			next.tried = len(next.order)
+			continue
+		}
+		if ok || len(s.memo) < maxMemo {
+			s.memo[string(key)] = s.score
+		}
+	}
+
+	if len(s.stack) == 0 && !s.optimal {
+		s.optimal = true
+		s.memo = nil
+	}
+}
+
+// Run expands a thousand nodes per generation until the search is
+// exhausted, generations are done, or ctx is done.
+func (s *Solver) Run(ctx context.Context, generations int) error {
+	var err error
+	for done := 0; !s.optimal && (generations == 0 || done < generations); done++ {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		s.Step(1)
+	}
+	s.Notify(solver.DoneEvent{Generation: s.generation, Score: s.bestScore, Err: err})
+	return err
+}
+
+// Path translates a Chromosome of direction genes into a valid Path
+func (s Solver) Path(c genetics.Chromosome) maps.Path {
+	p := maps.Path(make([]maps.Direction, 0, s.Map.StepsAllowed))
+	for _, g := range c.Genes {
+		p.Append(toDir(g))
+	}
+	p.Pad(s.Map)
+	return p
+}
+
+// Checkpoint saves the best path found so far. The search itself isn't
+// saved; a restored Solver starts over but prunes with the saved score.
+func (s *Solver) Checkpoint() solver.Checkpoint {
+	return solver.Checkpoint{
+		Best:       append([]genetics.Gene(nil), s.best.Genes...),
+		Score:      s.bestScore,
+		Generation: s.generation,
+		FoundAt:    s.found,
+		Seed:       s.Seed,
+
+		Alternatives: s.hall.Checkpoint(),
+	}
+}
+
+// Restore resumes from a Checkpoint. The search starts over but keeps
+// counting generations from the checkpoint, so a budget of generations
+// covers every run and the restarted search only gets what is left of it.
+func (s *Solver) Restore(cp solver.Checkpoint) error {
+	if cp.Best != nil {
+		// A boxed in start has a best path shorter than StepsAllowed
+		if len(cp.Best) > s.species.NumGenes {
+			return fmt.Errorf("exact.Solver.Restore(): checkpoint has %d genes but the species has %d", len(cp.Best), s.species.NumGenes)
+		}
+		for _, g := range cp.Best {
+			if g < 0 || g > 3 {
+				return fmt.Errorf("exact.Solver.Restore(): checkpoint has gene %d, which isn't a direction", g)
+			}
+		}
+		best := genetics.Chromosome{Species: s.species, Genes: append([]genetics.Gene(nil), cp.Best...)}
+		p := s.Path(best)
+		if score := p.Score(s.Map); score > s.bestScore {
+			s.best, s.bestScore, s.found = best, score, cp.FoundAt
+		}
+	}
+	s.generation = cp.Generation
+	s.hall.Restore(s.Map, cp.Alternatives)
+	return nil
+}
+
+// Result reports the best path and how much of the tree was searched.
+// Each search node counts as an evaluation.
+func (s *Solver) Result() solver.Result {
+	r := solver.Result{
+		Path:        s.Path(s.best),
+		Score:       s.bestScore,
+		UpperBound:  s.rootBound,
+		Optimal:     s.optimal,
+		Generations: s.generation,
+		Evaluations: s.nodes,
+		Elapsed:     s.elapsed,
+		FoundAt:     s.found,
+
+		Alternatives: s.hall.Entries(),
+	}
+	if s.optimal {
+		r.UpperBound = s.bestScore
+	}
+	return r
+}
+
+// Score accesses the current best score
+func (s *Solver) Score() int {
+	return s.bestScore
+}
+
+// Best returns the chromosome encoding the best path found so far.
+func (s *Solver) Best() genetics.Chromosome {
+	return s.best
+}
+
+// Optimal reports whether the search has finished, proving Score is
+// the best possible score for the map.
+func (s *Solver) Optimal() bool {
+	return s.optimal
+}
+
+// Nodes is the number of search nodes expanded so far.
+func (s *Solver) Nodes() int {
+	return s.nodes
+}
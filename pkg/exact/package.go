@@ -0,0 +1,6 @@
+// Package exact solves a Goldmine map with a full branch and bound search
+// over moves. When the search completes it proves the optimal score, which
+// gives a ground truth to measure the genetic solvers against. It proves
+// contest sized maps optimal, taking from under a second to about a minute
+// on each of the first ten in data/mastertests.txt.
+package exact
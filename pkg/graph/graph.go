@@ -9,35 +9,48 @@ import (
 )
 
 func init() {
-	solver.RegisterSolverFlag("graph", func(i solver.Input) solver.Solver {
-		return &Solver{Input: i}
+	solver.DefaultRegistry.Register(solver.Descriptor{
+		Name:        "graph",
+		Description: "evolves the order to visit points of interest along shortest paths",
+		Options: []solver.Option{{
+			Name:        "alleles",
+			Type:        solver.IntOption,
+			Default:     "1",
+			Description: "random chromosomes injected into the population each generation",
+		}},
+		New: func(i solver.Input, opts solver.Options) solver.Solver {
+			return &Solver{Genetic: solver.Genetic{Input: i}, alleles: opts.Int("alleles")}
+		},
 	})
 }
 
 // Solver reduces a map into a connectivity graph
 // via Init() and then steps through permutations.
 type Solver struct {
-	solver.Input
-	paths      [][]maps.Path
-	species    *genetics.Species
-	population []genetics.Chromosome
-	best       genetics.Chromosome
-	score      int
+	solver.Genetic
+	alleles  int
+	paths    [][]maps.Path
+	species  *genetics.Species
+	complete *maps.Completer
 }
 
 // Init creates the genetic components needed to solve a map and
 // reduces it to a graph.
 func (s *Solver) Init(popSize int) error {
+	if s.alleles < 0 {
+		return fmt.Errorf("graph.Solver.Init(): alleles must not be negative, got %d", s.alleles)
+	}
 	// -1 because we will always start at PoI[0]
 	nodes := len(s.Map.PointsOfInterest) - 1
 	s.species = genetics.NewSpecies(nodes, nodes-1)
-	s.population = make([]genetics.Chromosome, 0, popSize)
+	s.complete = maps.NewCompleter(s.Map)
+	population := make([]genetics.Chromosome, 0, popSize)
 	for i := 0; i < popSize; i++ {
 		c, err := s.species.NewPerm(s.Rand)
 		if err != nil {
 			return err
 		}
-		s.population = append(s.population, c)
+		population = append(population, c)
 	}
 
 	s.paths = make([][]maps.Path, len(s.Map.PointsOfInterest))
@@ -49,6 +62,12 @@ func (s *Solver) Init(popSize int) error {
 	for x, v := range s.Map.PointsOfInterest {
 		s.paths[x] = connectivityGraph(s.Map, poiLookup, v)
 	}
+	for i, p := range s.WarmStart {
+		if i == popSize {
+			break
+		}
+		encode(population[i], p, s.Map, poiLookup)
+	}
 
 	sum := 0
 	for _, x := range s.paths {
@@ -59,17 +78,66 @@ func (s *Solver) Init(popSize int) error {
 		}
 	}
 
-	fmt.Printf("Map has %d points of interest and %d meaningful paths\n", len(s.Map.PointsOfInterest), sum)
+	genome := solver.Genome{
+		Species: s.species,
+		Path: func(c genetics.Chromosome) maps.Path {
+			return s.path(c, s.complete)
+		},
+		Renew: s.renew,
+		Encode: func(c genetics.Chromosome, p maps.Path) {
+			encode(c, p, s.Map, poiLookup)
+		},
+	}
+	s.Start(genome, population, solver.InitEvent{
+		PointsOfInterest: len(s.Map.PointsOfInterest),
+		MeaningfulPaths:  sum,
+		Genes:            s.species.NumGenes,
+		PopulationSize:   popSize,
+	})
 
 	return nil
 }
 
+// encode reorders the genes of c to visit points of interest in the
+// order that p first reaches them
+func encode(c genetics.Chromosome, p maps.Path, m maps.Map, poiLookup map[maps.Vertex]int) {
+	used := make([]bool, len(c.Genes))
+	order := make([]genetics.Gene, 0, len(c.Genes))
+	v := m.PointsOfInterest[0]
+	for _, d := range p {
+		v = v.Move(d)
+		// poi[0] is the start and isn't a valid gene
+		if x, ok := poiLookup[v]; ok && x != 0 && !used[x-1] {
+			used[x-1] = true
+			order = append(order, genetics.Gene(x-1))
+		}
+	}
+	for _, g := range c.Genes {
+		if !used[g] {
+			order = append(order, g)
+		}
+	}
+	copy(c.Genes, order)
+}
+
 // Path exposes how this Solver would create a Path from a given Chromosome.
+// Unlike the Solver's own translations it has its own Completer, so it is
+// safe to call concurrently.
 func (s Solver) Path(c genetics.Chromosome) maps.Path {
+	return s.path(c, maps.NewCompleter(s.Map))
+}
+
+// path translates c, extending it with complete
+func (s Solver) path(c genetics.Chromosome, complete *maps.Completer) maps.Path {
 	p := maps.Path(make([]maps.Direction, 0, s.Map.StepsAllowed))
 
+	// Before Init there is no graph to follow, only the completion
+	genes := c.Genes
+	if s.paths == nil {
+		genes = nil
+	}
 	from := 0
-	for _, g := range c.Genes {
+	for _, g := range genes {
 		to := int(g + 1) // +1 because poi[0] isn't a valid gene
 		candidate := s.paths[from][to]
 		if candidate == nil || candidate.Len()+p.Len() > s.Map.StepsAllowed {
@@ -80,46 +148,21 @@ func (s Solver) Path(c genetics.Chromosome) maps.Path {
 	}
 
 	// In case we run out of valid genes before StepsAllowed
-	p.Pad(s.Map)
-
-	return p
+	return complete.Complete(p)
 }
 
-// Step iterates through count generations of evolution,
-// updating the population, score, and best path
-func (s *Solver) Step(count int) {
-	fitness := make([]genetics.Fitness, len(s.population))
-	for i := 0; i < count; i++ {
-		for n, c := range s.population {
-			p := s.Path(c)
-			score := p.Score(s.Map)
-			fitness[n] = genetics.Fitness(score)
-			if score > s.score {
-				s.score = score
-				s.best = c
-			}
-		}
-		s.Evolver.Evolve(s.Rand, s.population, fitness)
-
-		// Due to the massive search space, we need to inject new genes as a sort of
-		// alleling. Evolver currently doesn't have the right abstractions necessary
-		// to do age-out selection (we always kill off the weakest genes). We'll simulate
-		// age out selection by reeinitialzing one random gene every cycle.
-		victim := int(s.Rand.Int31n(int32(len(s.population))))
-		s.population[victim], _ = s.species.NewPerm(s.Rand)
+// renew replaces random chromosomes of the population with new ones.
+// Due to the massive search space, we need to inject new genes as a sort of
+// alleling. Evolver currently doesn't have the right abstractions necessary
+// to do age-out selection (we always kill off the weakest genes). We'll simulate
+// age out selection by reeinitialzing random genes every cycle.
+func (s *Solver) renew(population []genetics.Chromosome) {
+	for a := 0; a < s.alleles; a++ {
+		victim := int(s.Rand.Int31n(int32(len(population))))
+		population[victim], _ = s.species.NewPerm(s.Rand)
 	}
 }
 
-// Score accesses the current best score
-func (s *Solver) Score() int {
-	return s.score
-}
-
-// Best reveals the winning Chromosome
-func (s *Solver) Best() genetics.Chromosome {
-	return s.best
-}
-
 // connectivityGraph finds all the valid paths to all points of interest starting
 // at a particular vertex. If a path cannot be found within the map's maximum, the
 // slot for that Path is nil.
@@ -0,0 +1,412 @@
+package island
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/inlined/genetics"
+	"github.com/inlined/goldmine/pkg/maps"
+	"github.com/inlined/goldmine/pkg/solver"
+)
+
+// Topology decides which islands send migrants to which
+type Topology string
+
+const (
+	// Ring sends each island's migrants to the next island
+	Ring Topology = "ring"
+	// Full sends each island's migrants to every other island
+	Full Topology = "full"
+)
+
+// destinations lists the islands that island from sends migrants to
+func (t Topology) destinations(from, islands int) []int {
+	switch t {
+	case Ring:
+		if islands == 1 {
+			return nil
+		}
+		return []int{(from + 1) % islands}
+	default:
+		var to []int
+		for i := 0; i < islands; i++ {
+			if i != from {
+				to = append(to, i)
+			}
+		}
+		return to
+	}
+}
+
+func init() {
+	solver.DefaultRegistry.Register(solver.Descriptor{
+		Name:        "island",
+		Description: "evolves separate populations of a genetic solver in parallel with migration",
+		Options: []solver.Option{{
+			Name:        "solver",
+			Type:        solver.StringOption,
+			Default:     "graph",
+			Description: "genetic solver to run on each island",
+		}, {
+			Name:        "islands",
+			Type:        solver.IntOption,
+			Default:     "4",
+			Description: "number of populations",
+		}, {
+			Name:        "interval",
+			Type:        solver.IntOption,
+			Default:     "50",
+			Description: "generations each island runs between migrations",
+		}, {
+			Name:        "migrants",
+			Type:        solver.IntOption,
+			Default:     "2",
+			Description: "fittest chromosomes each island sends per migration",
+		}, {
+			Name:        "topology",
+			Type:        solver.StringOption,
+			Default:     string(Ring),
+			Description: "where migrants go: ring or full",
+		}},
+		New: func(i solver.Input, opts solver.Options) solver.Solver {
+			return &Solver{
+				Input:    i,
+				spec:     opts.String("solver"),
+				count:    opts.Int("islands"),
+				interval: opts.Int("interval"),
+				migrants: opts.Int("migrants"),
+				topology: Topology(opts.String("topology")),
+			}
+		},
+	})
+}
+
+// Solver evolves each island concurrently for interval generations at a
+// time, migrating between islands in between. Every generation an island
+// runs counts as a generation of the Solver, so it gets the same budget
+// as a single population would.
+type Solver struct {
+	solver.Input
+	solver.Observers
+	spec     string
+	count    int
+	interval int
+	migrants int
+	topology Topology
+
+	popSize int
+	islands []solver.Migrator
+	// ran counts the generations each island has run
+	ran []int
+	// stats collects each island's GenerationEvents during an epoch
+	stats      [][]solver.GenerationEvent
+	generation int
+	best       solver.Incumbent
+	found      int
+	elapsed    time.Duration
+}
+
+// Init creates and initializes every island, each with its own Rand
+// since they run concurrently.
+func (s *Solver) Init(popSize int) error {
+	switch {
+	case s.count < 1:
+		return fmt.Errorf("island.Solver.Init(): need at least one island, got %d", s.count)
+	case s.interval < 1:
+		return fmt.Errorf("island.Solver.Init(): interval must be positive, got %d", s.interval)
+	case s.migrants < 0:
+		return fmt.Errorf("island.Solver.Init(): migrants must not be negative, got %d", s.migrants)
+	case s.topology != Ring && s.topology != Full:
+		return fmt.Errorf("island.Solver.Init(): unknown topology %s", s.topology)
+	}
+	s.popSize = popSize
+	stats, err := s.populate()
+	if err != nil {
+		return err
+	}
+	s.Notify(stats)
+	return nil
+}
+
+// populate creates and initializes count islands, replacing any there were
+func (s *Solver) populate() (solver.InitEvent, error) {
+	s.islands = nil
+	s.ran = make([]int, s.count)
+	s.stats = make([][]solver.GenerationEvent, s.count)
+	s.best = solver.Incumbent{}
+	stats := solver.InitEvent{PointsOfInterest: len(s.Map.PointsOfInterest)}
+	for i := 0; i < s.count; i++ {
+		seed := int64(s.Rand.Int31n(1<<30))<<30 | int64(s.Rand.Int31n(1<<30))
+		input := s.Input
+		input.Rand = solver.NewRand(seed)
+		input.Seed = seed
+		sub, err := solver.DefaultRegistry.New(s.spec, input)
+		if err != nil {
+			return stats, fmt.Errorf("island.Solver.Init(): %s", err)
+		}
+		island, ok := sub.(solver.Migrator)
+		if !ok {
+			return stats, fmt.Errorf("island.Solver.Init(): %s doesn't evolve a population", s.spec)
+		}
+
+		i := i
+		island.Subscribe(solver.ObserverFunc(func(e solver.Event) {
+			switch e := e.(type) {
+			case solver.GenerationEvent:
+				s.stats[i] = append(s.stats[i], e)
+			case solver.InitEvent:
+				stats.MeaningfulPaths = e.MeaningfulPaths
+				stats.Genes = e.Genes
+				stats.PopulationSize += e.PopulationSize
+			}
+		}))
+		if err := island.Init(s.popSize); err != nil {
+			return stats, err
+		}
+		s.islands = append(s.islands, island)
+		s.best.Offer(island, island.Score())
+	}
+	s.best.Improved()
+	return stats, nil
+}
+
+// Step runs count generations across the islands
+func (s *Solver) Step(count int) {
+	s.evolve(context.Background(), count)
+}
+
+// Run evolves the islands for up to generations generations between
+// them, or until ctx is done if generations is 0.
+func (s *Solver) Run(ctx context.Context, generations int) error {
+	err := s.evolve(ctx, generations)
+	s.Notify(solver.DoneEvent{Generation: s.generation, Score: s.Score(), Err: err})
+	return err
+}
+
+// evolve runs the islands in epochs that end at each migration. Islands
+// take turns when there aren't enough generations left to go around, so
+// however the generations are split up each island runs as many and
+// migration happens at the same points.
+func (s *Solver) evolve(ctx context.Context, generations int) error {
+	for done := 0; generations == 0 || done < generations; {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		left := generations - done
+		if generations == 0 {
+			left = s.interval * len(s.islands)
+		}
+		ran, err := s.runEpoch(ctx, s.plan(left))
+		done += ran
+		if err != nil || ran == 0 {
+			return err
+		}
+		if s.ran[0] != s.ran[len(s.ran)-1] || s.ran[0]%s.interval != 0 {
+			continue
+		}
+		if err := s.migrate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// plan decides how many of the next total generations each island runs.
+// Islands that are behind catch up first, then every island runs until
+// the next migration if there are enough generations for all of them.
+func (s *Solver) plan(total int) []int {
+	counts := make([]int, len(s.islands))
+	// Islands only ever fall one generation behind the first
+	behind := s.ran[0] != s.ran[len(s.ran)-1]
+	each := s.interval - s.ran[0]%s.interval
+	if !behind && total/len(counts) < each {
+		each = total / len(counts)
+	}
+	for i := range counts {
+		switch {
+		case behind && s.ran[i] == s.ran[0]:
+		case behind || each == 0:
+			if total > 0 {
+				counts[i] = 1
+				total--
+			}
+		default:
+			counts[i] = each
+		}
+	}
+	return counts
+}
+
+// runEpoch runs every island concurrently for its count of generations
+// and then reports each generation they ran. Returns how many that was.
+func (s *Solver) runEpoch(ctx context.Context, counts []int) (int, error) {
+	start := time.Now()
+	defer func() {
+		s.elapsed += time.Since(start)
+	}()
+	errs := make([]error, len(s.islands))
+	var wg sync.WaitGroup
+	for i, island := range s.islands {
+		s.stats[i] = s.stats[i][:0]
+		if counts[i] == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, island solver.Migrator) {
+			defer wg.Done()
+			errs[i] = island.Run(ctx, counts[i])
+		}(i, island)
+	}
+	wg.Wait()
+	for _, island := range s.islands {
+		s.best.Offer(island, island.Score())
+	}
+
+	// Each island's generation is reported with how every island that
+	// ran that far stood at the same point of the epoch
+	ran, longest := 0, 0
+	for i, st := range s.stats {
+		s.ran[i] += len(st)
+		ran += len(st)
+		if len(st) > longest {
+			longest = len(st)
+		}
+	}
+	improved := s.best.Improved()
+	reported := 0
+	for g := 0; g < longest; g++ {
+		var e solver.GenerationEvent
+		var at []solver.GenerationEvent
+		for _, st := range s.stats {
+			if g < len(st) {
+				at = append(at, st[g])
+			}
+		}
+		e.Best, e.Worst = at[0].Best, at[0].Worst
+		for _, ie := range at {
+			if ie.Best > e.Best {
+				e.Best = ie.Best
+			}
+			if ie.Worst < e.Worst {
+				e.Worst = ie.Worst
+			}
+			e.Mean += ie.Mean / float64(len(at))
+		}
+		for range at {
+			reported++
+			if reported == ran && improved {
+				s.found = s.generation
+				if s.Observed() {
+					s.Notify(solver.ImprovementEvent{Generation: s.generation, Score: s.Score(), Path: s.Path(s.Best())})
+				}
+			}
+			e.Generation = s.generation
+			s.Notify(e)
+			s.generation++
+		}
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			return ran, err
+		}
+	}
+	return ran, nil
+}
+
+// migrate sends the fittest chromosomes of each island along the topology
+func (s *Solver) migrate() error {
+	if s.migrants == 0 {
+		return nil
+	}
+	emigrants := make([][][]genetics.Gene, len(s.islands))
+	for i, island := range s.islands {
+		emigrants[i] = island.Emigrants(s.migrants)
+	}
+	for from := range s.islands {
+		for _, to := range s.topology.destinations(from, len(s.islands)) {
+			if err := s.islands[to].Immigrate(emigrants[from]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Path asks whichever island made c to translate it
+func (s *Solver) Path(c genetics.Chromosome) maps.Path {
+	return s.best.Path(c)
+}
+
+// Score is the best score on any island
+func (s *Solver) Score() int {
+	return s.best.Score()
+}
+
+// Best is the best chromosome on any island
+func (s *Solver) Best() genetics.Chromosome {
+	return s.best.Best()
+}
+
+// Result reports the best path on any island along with the work of
+// every island
+func (s *Solver) Result() solver.Result {
+	r := solver.Result{
+		Score:       s.Score(),
+		Generations: s.generation,
+		Elapsed:     s.elapsed,
+		FoundAt:     s.found,
+	}
+	if c := s.Best(); c.Species != nil {
+		r.Path = s.Path(c)
+	}
+	hall := solver.HallOfFame{Size: s.Alternatives}
+	for _, island := range s.islands {
+		ir := island.Result()
+		r.Evaluations += ir.Evaluations
+		hall.Merge(ir.Alternatives)
+	}
+	r.Alternatives = hall.Entries()
+	return r
+}
+
+// Checkpoint saves every island
+func (s *Solver) Checkpoint() solver.Checkpoint {
+	cp := solver.Checkpoint{
+		Score:      s.Score(),
+		Generation: s.generation,
+		FoundAt:    s.found,
+		Seed:       s.Seed,
+	}
+	for _, island := range s.islands {
+		cp.Members = append(cp.Members, island.Checkpoint())
+	}
+	return cp
+}
+
+// Restore resumes every island from a Checkpoint, first replacing the
+// islands if the checkpoint has a different number of them.
+func (s *Solver) Restore(cp solver.Checkpoint) error {
+	if len(cp.Members) == 0 {
+		return fmt.Errorf("island.Solver.Restore(): checkpoint has no islands")
+	}
+	if len(cp.Members) != len(s.islands) {
+		s.count = len(cp.Members)
+		if _, err := s.populate(); err != nil {
+			return err
+		}
+	}
+	for i, island := range s.islands {
+		if err := island.Restore(cp.Members[i]); err != nil {
+			return err
+		}
+		s.ran[i] = cp.Members[i].Generation
+		s.best.Offer(island, island.Score())
+	}
+	s.generation = cp.Generation
+	s.found = cp.FoundAt
+	s.best.Improved()
+	return nil
+}
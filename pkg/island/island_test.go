@@ -0,0 +1,125 @@
+package island_test
+
+import (
+	"context"
+	mrand "math/rand"
+	"strings"
+	"testing"
+
+	"github.com/inlined/genetics"
+	"github.com/inlined/goldmine/pkg/maps"
+	"github.com/inlined/goldmine/pkg/solver"
+
+	_ "github.com/inlined/goldmine/pkg/bruteforce"
+	_ "github.com/inlined/goldmine/pkg/exact"
+	_ "github.com/inlined/goldmine/pkg/graph"
+	_ "github.com/inlined/goldmine/pkg/island"
+)
+
+const testMap = `=4,5,8
+	w...1
+	..s.9
+	2d1..
+	.w..3`
+
+func newSolver(t *testing.T, spec string) (solver.Solver, maps.Map) {
+	r := maps.NewReader(strings.NewReader(testMap))
+	m, err := r.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The zero flags give the command line's defaults
+	var selection genetics.NaturalSelectionFlag
+	var crossover genetics.CrossoverFlag
+	var mutation genetics.MutationFlag
+	s, err := solver.DefaultRegistry.New(spec, solver.Input{
+		Map: m,
+		Evolver: genetics.Evolver{
+			ReplacementCount: 5,
+			MutationRate:     0.1,
+			Selector:         selection.Get(),
+			Crossover:        crossover.Get(),
+			Mutator:          mutation.Get(),
+		},
+		Rand: mrand.New(mrand.NewSource(1)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, m
+}
+
+func TestIslands(t *testing.T) {
+	for _, spec := range []string{
+		"island(solver=graph,islands=3,interval=4,topology=ring)",
+		"island(solver=bruteforce,islands=3,interval=4,topology=full)",
+		"island(islands=1,migrants=0)",
+	} {
+		t.Run(spec, func(t *testing.T) {
+			s, m := newSolver(t, spec)
+			var generations []int
+			s.Subscribe(solver.ObserverFunc(func(e solver.Event) {
+				if e, ok := e.(solver.GenerationEvent); ok {
+					generations = append(generations, e.Generation)
+				}
+			}))
+			if err := s.Init(10); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.Run(context.Background(), 10); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(generations) != 10 || generations[9] != 9 {
+				t.Errorf("saw generations %v; want 0 through 9", generations)
+			}
+			p := s.Path(s.Best())
+			if got := p.Score(m); got != s.Score() {
+				t.Errorf("Path(Best()) scores %d; Score() is %d", got, s.Score())
+			}
+		})
+	}
+}
+
+func TestBadOptions(t *testing.T) {
+	for _, spec := range []string{
+		"island(solver=exact)",
+		"island(solver=missing)",
+		"island(islands=0)",
+		"island(interval=0)",
+		"island(migrants=-1)",
+		"island(topology=star)",
+	} {
+		t.Run(spec, func(t *testing.T) {
+			s, _ := newSolver(t, spec)
+			if err := s.Init(10); err == nil {
+				t.Errorf("Init() accepted %s", spec)
+			}
+		})
+	}
+}
+
+func TestRestoreIslandCount(t *testing.T) {
+	s, _ := newSolver(t, "island(islands=3,interval=4)")
+	if err := s.Init(10); err != nil {
+		t.Fatal(err)
+	}
+	s.Step(7)
+	cp := s.Checkpoint()
+
+	// A machine with a different default still resumes with the islands
+	// the checkpoint was made with
+	restored, _ := newSolver(t, "island(islands=2,interval=4)")
+	if err := restored.Init(10); err != nil {
+		t.Fatal(err)
+	}
+	if err := restored.Restore(cp); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(restored.Checkpoint().Members); got != 3 {
+		t.Errorf("restored solver has %d islands; want 3", got)
+	}
+	if restored.Score() != s.Score() || restored.Result().Generations != 7 {
+		t.Errorf("restored solver scores %d at generation %d; want %d at 7", restored.Score(), restored.Result().Generations, s.Score())
+	}
+}
@@ -0,0 +1,10 @@
+// Package island runs several independent populations of a genetic
+// solver in parallel and periodically migrates the fittest chromosomes
+// between them. Keeping the populations apart fights premature
+// convergence while the migrants spread good building blocks around.
+//
+// Select it with --strategy=island(solver=graph,islands=4,interval=50,
+// migrants=2,topology=ring), where every option is optional, or wrap any
+// other --strategy with the --islands, --migration_interval, --migrants,
+// and --topology flags.
+package island
@@ -0,0 +1,313 @@
+package maps
+
+import "sort"
+
+// Distances finds the walking distance from v to every cell in m, indexed
+// by Row*Cols()+Col. Cells that can't be reached are -1.
+func (m Map) Distances(from Vertex) []int {
+	dist := make([]int, m.Rows()*m.Cols())
+	for i := range dist {
+		dist[i] = -1
+	}
+	dist[from.Row*m.Cols()+from.Col] = 0
+	queue := []Vertex{from}
+	for len(queue) != 0 {
+		v := queue[0]
+		queue = queue[1:]
+		for _, d := range []Direction{Up, Down, Left, Right} {
+			v2 := v.Move(d)
+			if !m.CanBeAt(v2) || dist[v2.Row*m.Cols()+v2.Col] != -1 {
+				continue
+			}
+			dist[v2.Row*m.Cols()+v2.Col] = dist[v.Row*m.Cols()+v.Col] + 1
+			queue = append(queue, v2)
+		}
+	}
+	return dist
+}
+
+// Analysis describes what any path on a map could possibly achieve
+type Analysis struct {
+	// Reachable lists the cells within StepsAllowed of the start
+	Reachable []Vertex
+
+	// ReachableValue is the sum of the digits in Reachable
+	ReachableValue int
+
+	// ReachablePickaxes is the number of pickaxes in Reachable
+	ReachablePickaxes int
+
+	// PickaxesBefore is indexed like Map.PointsOfInterest and holds the
+	// most pickaxes that could be held when that point is collected, or
+	// -1 if the point can't be reached.
+	PickaxesBefore []int
+
+	// UpperBound is a score no path on the map can beat
+	UpperBound int
+}
+
+// detour is a pickaxe a path can walk to within its steps
+type detour struct {
+	cell        int
+	toPickaxe   int
+	fromPickaxe []int
+}
+
+// digit is a digit a path can walk to within its steps, along with how
+// many pickaxes could be collected before it
+type digit struct {
+	val, dist, before int
+}
+
+// Analyze computes the reachability and score bounds of m. A pickaxe
+// can only come before a digit if a path can walk from the start to
+// the pickaxe and then on to the digit within the allowed steps.
+func Analyze(m Map) Analysis {
+	var a Analysis
+	fromStart := m.Distances(m.PointsOfInterest[0])
+	for row := 0; row < m.Rows(); row++ {
+		for col := 0; col < m.Cols(); col++ {
+			v := Vertex{Row: row, Col: col}
+			d := fromStart[row*m.Cols()+col]
+			if d < 0 || d > m.StepsAllowed {
+				continue
+			}
+			a.Reachable = append(a.Reachable, v)
+			switch x := m.At(v); x {
+			case Start, Space:
+			case Pickaxe:
+				a.ReachablePickaxes++
+			default:
+				a.ReachableValue += int(x - '0')
+			}
+		}
+	}
+
+	var detours []detour
+	for _, v := range m.PointsOfInterest[1:] {
+		d := fromStart[v.Row*m.Cols()+v.Col]
+		if m.At(v) == Pickaxe && d >= 0 && d <= m.StepsAllowed {
+			detours = append(detours, detour{cell: v.Row*m.Cols() + v.Col, toPickaxe: d, fromPickaxe: m.Distances(v)})
+		}
+	}
+
+	a.PickaxesBefore = make([]int, len(m.PointsOfInterest))
+	var digits []digit
+	for x, v := range m.PointsOfInterest {
+		i := v.Row*m.Cols() + v.Col
+		if d := fromStart[i]; d < 0 || d > m.StepsAllowed {
+			a.PickaxesBefore[x] = -1
+			continue
+		}
+		before := 0
+		for _, d := range detours {
+			if d.fromPickaxe[i] > 0 && d.toPickaxe+d.fromPickaxe[i] <= m.StepsAllowed {
+				before++
+			}
+		}
+		// Every pickaxe takes a step before the point is collected
+		if before > m.StepsAllowed-1 {
+			before = m.StepsAllowed - 1
+		}
+		a.PickaxesBefore[x] = before
+
+		if val := m.At(v); val >= '0' && val <= '9' {
+			digits = append(digits, digit{val: int(val - '0'), dist: fromStart[i], before: before})
+		}
+	}
+
+	a.UpperBound = upperBound(m, detours, digits)
+	if b := detourBound(m, a.ReachablePickaxes, digits); b < a.UpperBound {
+		a.UpperBound = b
+	}
+	return a
+}
+
+// detourBound bounds the score of any path on m by giving every digit as
+// many doublings as there are pickaxes it could come after. A path that
+// collects k pickaxes has k fewer steps to collect digits with, and none
+// of them is worth more than k doublings.
+func detourBound(m Map, pickaxes int, digits []digit) int {
+	best := 0
+	values := make([]int, len(digits))
+	for k := 0; k <= pickaxes && k <= m.StepsAllowed; k++ {
+		for i, d := range digits {
+			shift := d.before
+			if shift > k {
+				shift = k
+			}
+			values[i] = saturatingShift(d.val, shift)
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(values)))
+		total := 0
+		for i, val := range values {
+			if i == m.StepsAllowed-k {
+				break
+			}
+			total = saturatingAdd(total, val)
+		}
+		if total > best {
+			best = total
+		}
+	}
+	return best
+}
+
+// upperBound bounds the score of any path on m by when pickaxes can be
+// collected rather than where. A path that collects k
+// pickaxes has k fewer steps to collect digits with, so it collects at
+// most StepsAllowed-k of them, each on a step no earlier than its distance
+// from the start. The digit collected on step t can't be worth more
+// doublings than pickaxes could be collected in the t-1 steps before it.
+// The bound takes the most valuable digits that fit on the steps that are
+// left and pairs the most valuable with the most doublings.
+func upperBound(m Map, pickaxes []detour, digits []digit) int {
+	steps := m.StepsAllowed
+	collectable := collectablePickaxes(m, pickaxes)
+	sort.SliceStable(digits, func(i, j int) bool {
+		return digits[i].val > digits[j].val
+	})
+	// fitting[t] counts the chosen digits that need step t or later
+	fitting := make([]int, steps+1)
+	best := 0
+	for k := 0; k < len(collectable) && collectable[k] <= steps; k++ {
+		for t := range fitting {
+			fitting[t] = 0
+		}
+		total, t := 0, steps
+		for _, d := range digits {
+			if t == k {
+				break
+			}
+			fits := true
+			for u := 1; u <= d.dist; u++ {
+				// Only the steps after the first k can collect digits
+				free := steps - u + 1
+				if u <= k {
+					free = steps - k
+				}
+				if fitting[u] == free {
+					fits = false
+					break
+				}
+			}
+			if !fits {
+				continue
+			}
+			for u := 1; u <= d.dist; u++ {
+				fitting[u]++
+			}
+			doublings := k
+			for collectable[doublings] > t-1 {
+				doublings--
+			}
+			total = saturatingAdd(total, saturatingShift(d.val, doublings))
+			t--
+		}
+		if total > best {
+			best = total
+		}
+	}
+	return best
+}
+
+// collectablePickaxes lists a lower bound on the steps it takes to collect
+// each number of pickaxes, up to as many as fit in StepsAllowed. Collecting
+// j pickaxes takes at least j steps and at least the walk to the jth
+// nearest. The walk from one pickaxe to the next is never shorter than the
+// distance between them, which is at least the distance from the next to
+// its nearest neighbour, and never goes straight back to the one before.
+func collectablePickaxes(m Map, pickaxes []detour) []int {
+	nearest := make([]int, len(pickaxes))
+	neighbour := make([]int, len(pickaxes))
+	for p, from := range pickaxes {
+		nearest[p] = from.toPickaxe
+		neighbour[p] = m.StepsAllowed + 1
+		for q, to := range pickaxes {
+			if d := to.fromPickaxe[from.cell]; q != p && d >= 0 && d < neighbour[p] {
+				neighbour[p] = d
+			}
+		}
+	}
+	sort.Ints(nearest)
+	sort.Ints(neighbour)
+
+	// walk[p] is the fewest steps from pickaxe p that collect the last j
+	// pickaxes, p included, and then is the pickaxe it goes to next or -1.
+	// other is the fewest that go to some other pickaxe next.
+	type tour struct {
+		walk, then, other int
+	}
+	const never = -1
+	last := make([]tour, len(pickaxes))
+	next := make([]tour, len(pickaxes))
+	for p := range last {
+		last[p] = tour{walk: 0, then: -1, other: never}
+	}
+	need := []int{0}
+	for j := 1; j <= len(pickaxes) && need[j-1] < m.StepsAllowed; j++ {
+		fewest := never
+		for p, from := range pickaxes {
+			if j > 1 {
+				next[p] = tour{walk: never, then: -1, other: never}
+				for q, to := range pickaxes {
+					d := from.fromPickaxe[to.cell]
+					// Going on from q must not come straight back to p
+					rest := last[q].walk
+					if last[q].then == p {
+						rest = last[q].other
+					}
+					if q == p || d < 0 || rest == never {
+						continue
+					}
+					switch walk := d + rest; {
+					case next[p].walk == never || walk < next[p].walk:
+						next[p].other = next[p].walk
+						next[p].walk, next[p].then = walk, q
+					case next[p].other == never || walk < next[p].other:
+						next[p].other = walk
+					}
+				}
+			} else {
+				next[p] = last[p]
+			}
+			if w := next[p].walk; w != never && (fewest == never || from.toPickaxe+w < fewest) {
+				fewest = from.toPickaxe + w
+			}
+		}
+		if fewest == never {
+			break
+		}
+		gaps := nearest[0]
+		for _, d := range neighbour[:j-1] {
+			gaps += d
+		}
+		for _, floor := range []int{need[j-1] + 1, nearest[j-1], gaps} {
+			if fewest < floor {
+				fewest = floor
+			}
+		}
+		need = append(need, fewest)
+		last, next = next, last
+	}
+	return need
+}
+
+// maxInt is the largest score an int can hold
+const maxInt = int(^uint(0) >> 1)
+
+// saturatingShift is val doubled shift times, or maxInt if that overflows
+func saturatingShift(val, shift int) int {
+	if val > maxInt>>uint(shift) {
+		return maxInt
+	}
+	return val << uint(shift)
+}
+
+// saturatingAdd is a+b for non-negative a and b, or maxInt if that overflows
+func saturatingAdd(a, b int) int {
+	if a > maxInt-b {
+		return maxInt
+	}
+	return a + b
+}
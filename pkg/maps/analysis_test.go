@@ -0,0 +1,133 @@
+package maps_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/goldmine/pkg/maps"
+)
+
+func TestDistances(t *testing.T) {
+	s := `=3,4,5
+		  s.w3
+		  .ww.
+		  ...d`
+	r := maps.NewReader(strings.NewReader(s))
+	m, err := r.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []int{
+		0, 1, -1, 7,
+		1, -1, -1, 6,
+		2, 3, 4, 5,
+	}
+	if diff := cmp.Diff(m.Distances(m.PointsOfInterest[0]), want); diff != "" {
+		t.Errorf("Distances() returned wrong distances; diff=%s", diff)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	for _, test := range []struct {
+		tag      string
+		m        string
+		analysis maps.Analysis
+	}{
+		{
+			tag: "out of reach",
+			m: `=1,6,2
+				9.s.19`,
+			analysis: maps.Analysis{
+				Reachable:      coordinates(0, 0, 0, 1, 0, 2, 0, 3, 0, 4),
+				ReachableValue: 10,
+				PickaxesBefore: []int{0, 0, 0, -1},
+				// Each 2 step path only reaches one of the 9 and the 1
+				UpperBound: 9,
+			},
+		}, {
+			tag: "pickaxe detour",
+			m: `=1,5,4
+				9.sd1`,
+			analysis: maps.Analysis{
+				Reachable:         coordinates(0, 0, 0, 1, 0, 2, 0, 3, 0, 4),
+				ReachableValue:    10,
+				ReachablePickaxes: 1,
+				// Walking to the pickaxe and back to the 9 takes 4 steps
+				PickaxesBefore: []int{1, 1, 0, 1},
+				UpperBound:     20,
+			},
+		}, {
+			tag: "pickaxe takes a digit's step",
+			m: `=1,4,2
+				9sd9`,
+			analysis: maps.Analysis{
+				Reachable:         coordinates(0, 0, 0, 1, 0, 2, 0, 3),
+				ReachableValue:    18,
+				ReachablePickaxes: 1,
+				PickaxesBefore:    []int{1, 0, 0, 1},
+				// Collecting the pickaxe leaves one step for one of the 9s
+				UpperBound: 18,
+			},
+		}, {
+			tag: "pickaxe too far to help",
+			m: `=1,6,4
+				d..s99`,
+			analysis: maps.Analysis{
+				Reachable:         coordinates(0, 0, 0, 1, 0, 2, 0, 3, 0, 4, 0, 5),
+				ReachableValue:    18,
+				ReachablePickaxes: 1,
+				PickaxesBefore:    []int{0, 0, 0, 0},
+				UpperBound:        18,
+			},
+		}, {
+			tag: "pickaxes take steps to collect",
+			m: `=1,9,6
+				d.d.sd999`,
+			analysis: maps.Analysis{
+				Reachable:         coordinates(0, 0, 0, 1, 0, 2, 0, 3, 0, 4, 0, 5, 0, 6, 0, 7, 0, 8),
+				ReachableValue:    27,
+				ReachablePickaxes: 3,
+				PickaxesBefore:    []int{2, 2, 2, 1, 2, 1, 1},
+				// A second pickaxe takes 5 steps to collect, so only a 9
+				// collected last could be worth 4 times as much
+				UpperBound: 72,
+			},
+		}, {
+			tag: "more digits than steps",
+			m: `=1,5,2
+				19s91`,
+			analysis: maps.Analysis{
+				Reachable:      coordinates(0, 0, 0, 1, 0, 2, 0, 3, 0, 4),
+				ReachableValue: 20,
+				PickaxesBefore: []int{0, 0, 0, 0, 0},
+				UpperBound:     18,
+			},
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			r := maps.NewReader(strings.NewReader(test.m))
+			m, err := r.Next()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(maps.Analyze(m), test.analysis); diff != "" {
+				t.Errorf("Analyze() returned the wrong analysis; diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestAnalyzeSaturates(t *testing.T) {
+	const pickaxes = 70
+	r := maps.NewReader(strings.NewReader(fmt.Sprintf("=1,%d,%d\ns%s9", pickaxes+2, pickaxes+2, strings.Repeat("d", pickaxes))))
+	m, err := r.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := maps.Analyze(m).UpperBound, int(^uint(0)>>1); got != want {
+		t.Errorf("Analyze().UpperBound = %d; want the largest int %d", got, want)
+	}
+}
@@ -0,0 +1,82 @@
+package maps
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// AnswerComment starts lines of an answer file that hold notes, such as
+// how the answers were made, rather than paths.
+const AnswerComment = "#"
+
+// Answer is a Path submitted as the solution to a Map
+type Answer struct {
+	Map  Map
+	Path Path
+}
+
+// AnswerReader pairs each path in an answer file with its map
+type AnswerReader struct {
+	maps    Reader
+	answers *bufio.Reader
+	count   int
+}
+
+// NewAnswerReader creates a new maps.AnswerReader. Maps are read from
+// maps in the same format as maps.Reader and paths are read one per
+// line from answers. Lines starting with # are comments and are skipped.
+func NewAnswerReader(maps, answers io.Reader) AnswerReader {
+	return AnswerReader{
+		maps:    NewReader(maps),
+		answers: bufio.NewReader(answers),
+	}
+}
+
+// Next reads the next map and its answer. Returns io.EOF once every
+// map has been read. Paths are not validated; an answer may walk into
+// walls or contain characters that aren't directions.
+func (r *AnswerReader) Next() (Answer, error) {
+	var a Answer
+	var err error
+
+	a.Map, err = r.maps.Next()
+	if err == io.EOF {
+		// There shouldn't be any more answers than maps
+		for {
+			line, err := r.readLine()
+			if strings.TrimSpace(line) != "" {
+				return a, fmt.Errorf("AnswerReader.Next(): found more answers than the %d maps", r.count)
+			}
+			if err != nil {
+				return a, io.EOF
+			}
+		}
+	}
+	if err != nil {
+		return a, err
+	}
+
+	line, err := r.readLine()
+	if err != nil && (err != io.EOF || line == "") {
+		if err == io.EOF {
+			return a, fmt.Errorf("AnswerReader.Next(): no answer for map %d", r.count)
+		}
+		return a, fmt.Errorf("AnswerReader.Next(): failed to read answer %d: %s", r.count, err)
+	}
+	a.Path = ParsePath(strings.TrimSpace(line))
+	r.count++
+
+	return a, nil
+}
+
+// readLine reads the next line of answers that isn't a comment
+func (r *AnswerReader) readLine() (string, error) {
+	for {
+		line, err := r.answers.ReadString('\n')
+		if err != nil || !strings.HasPrefix(line, AnswerComment) {
+			return line, err
+		}
+	}
+}
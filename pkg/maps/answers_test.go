@@ -0,0 +1,77 @@
+package maps_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/inlined/goldmine/pkg/maps"
+)
+
+const twoMaps = `=2,2,2
+				 ..
+				 s.
+
+				 =1,2,1
+				 s1`
+
+func TestAnswerReader(t *testing.T) {
+	for _, test := range []struct {
+		tag     string
+		answers string
+		paths   []string
+		err     string
+	}{
+		{
+			tag:     "one per map",
+			answers: "ur\nr\n",
+			paths:   []string{"ur", "r"},
+		}, {
+			tag:     "no trailing newline",
+			answers: "ur\nr",
+			paths:   []string{"ur", "r"},
+		}, {
+			tag:     "empty answer",
+			answers: "\nr\n",
+			paths:   []string{"", "r"},
+		}, {
+			tag:     "illegal characters are kept",
+			answers: "ux\nr\n",
+			paths:   []string{"ux", "r"},
+		}, {
+			tag:     "comments are skipped",
+			answers: "# seed=1\nur\n# more\nr\n# trailing\n",
+			paths:   []string{"ur", "r"},
+		}, {
+			tag:     "too few answers",
+			answers: "ur\n",
+			paths:   []string{"ur"},
+			err:     "AnswerReader.Next(): no answer for map 1",
+		}, {
+			tag:     "too many answers",
+			answers: "ur\nr\nl\n",
+			paths:   []string{"ur", "r"},
+			err:     "AnswerReader.Next(): found more answers than the 2 maps",
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			r := maps.NewAnswerReader(strings.NewReader(twoMaps), strings.NewReader(test.answers))
+			var got []string
+			var err error
+			var a maps.Answer
+			for a, err = r.Next(); err == nil; a, err = r.Next() {
+				got = append(got, a.Path.String())
+			}
+
+			if test.err == "" && err != io.EOF {
+				t.Errorf("maps.AnswerReader.Next(): expected EOF; got %s", err)
+			}
+			if test.err != "" && (err == nil || err.Error() != test.err) {
+				t.Errorf("maps.AnswerReader.Next(): expected err %s; got %v", test.err, err)
+			}
+			if strings.Join(got, ",") != strings.Join(test.paths, ",") {
+				t.Errorf("maps.AnswerReader.Next(): got paths %v; want %v", got, test.paths)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package maps
+
+// Completer extends paths to use all of a map's steps. Rather than
+// bouncing back and forth like Pad, it greedily walks to whichever
+// uncollected point of interest earns the most per step.
+// A Completer reuses its memory between calls and is not safe for
+// concurrent use.
+type Completer struct {
+	m      Map
+	e      *Evaluator
+	dist   []int
+	parent []Direction
+	queue  []Vertex
+	walk   []Direction
+}
+
+// NewCompleter creates a Completer for paths on m
+func NewCompleter(m Map) *Completer {
+	c := &Completer{
+		m:      m,
+		e:      NewEvaluator(m),
+		dist:   make([]int, m.Rows()*m.Cols()),
+		parent: make([]Direction, m.Rows()*m.Cols()),
+	}
+	for i := range c.dist {
+		c.dist[i] = -1
+	}
+	return c
+}
+
+// Complete returns p extended to m.StepsAllowed steps. Once nothing
+// more can be collected, or if p is invalid, the remaining steps are
+// filled with Pad.
+func (c *Completer) Complete(p Path) Path {
+	p = p.Copy()
+	if p.Len() >= c.m.StepsAllowed {
+		return p
+	}
+	c.e.Reset()
+	for _, d := range p {
+		if !c.e.Apply(d) {
+			p.Pad(c.m)
+			return p
+		}
+	}
+
+	for p.Len() < c.m.StepsAllowed {
+		walk := c.next(c.m.StepsAllowed - p.Len())
+		if walk == nil {
+			break
+		}
+		for _, d := range walk {
+			c.e.Apply(d)
+			p.Append(d)
+		}
+	}
+
+	p.Pad(c.m)
+	return p
+}
+
+// next searches outward from the evaluator's position for the uncollected
+// point of interest worth the most per step and returns the moves that
+// walk there. Returns nil if nothing is within remaining steps.
+func (c *Completer) next(remaining int) []Direction {
+	m := c.m
+	from := c.e.Position()
+	start := from.Row*m.Cols() + from.Col
+	c.dist[start] = 0
+	c.queue = append(c.queue[:0], from)
+
+	var best Vertex
+	bestValue, bestDist := 0, 0
+	bestPickaxe, bestPickaxeDist := InvalidVertex, 0
+	for head := 0; head < len(c.queue); head++ {
+		v := c.queue[head]
+		d := c.dist[v.Row*m.Cols()+v.Col]
+		if d != 0 && !c.e.Visited(v) {
+			switch x := m.At(v); x {
+			case Space, Start, Wall:
+			case Pickaxe:
+				if !bestPickaxe.Valid() {
+					bestPickaxe, bestPickaxeDist = v, d
+				}
+			default:
+				value := int(x-'0') << uint(c.e.Pickaxes())
+				// Compare value/dist without dividing
+				if value*bestDist > bestValue*d || bestValue == 0 {
+					best, bestValue, bestDist = v, value, d
+				}
+			}
+		}
+		if d == remaining {
+			continue
+		}
+		for _, dir := range []Direction{Up, Down, Left, Right} {
+			v2 := v.Move(dir)
+			if !m.CanBeAt(v2) || c.dist[v2.Row*m.Cols()+v2.Col] != -1 {
+				continue
+			}
+			c.dist[v2.Row*m.Cols()+v2.Col] = d + 1
+			c.parent[v2.Row*m.Cols()+v2.Col] = dir
+			c.queue = append(c.queue, v2)
+		}
+	}
+
+	// Pickaxes aren't worth anything on their own, but they double
+	// everything collected afterwards. Detour for one if it is less than
+	// twice as far as the best digit.
+	target := best
+	if bestPickaxe.Valid() && bestPickaxeDist < 2*bestDist {
+		target = bestPickaxe
+	}
+
+	// Walk back from the target to recover the moves
+	c.walk = c.walk[:0]
+	if bestValue != 0 {
+		for v := target; v != from; {
+			dir := c.parent[v.Row*m.Cols()+v.Col]
+			c.walk = append(c.walk, dir)
+			v = v.Move(opposite(dir))
+		}
+		for i, j := 0, len(c.walk)-1; i < j; i, j = i+1, j-1 {
+			c.walk[i], c.walk[j] = c.walk[j], c.walk[i]
+		}
+	}
+
+	for _, v := range c.queue {
+		c.dist[v.Row*m.Cols()+v.Col] = -1
+	}
+	if len(c.walk) == 0 {
+		return nil
+	}
+	return c.walk
+}
+
+func opposite(d Direction) Direction {
+	switch d {
+	case Up:
+		return Down
+	case Down:
+		return Up
+	case Left:
+		return Right
+	default:
+		return Left
+	}
+}
+
+// Complete is a convenience for extending a single path with a Completer.
+// Solvers that complete many paths should keep their own Completer.
+func (p *Path) Complete(m Map) {
+	*p = NewCompleter(m).Complete(*p)
+}
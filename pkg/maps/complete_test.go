@@ -0,0 +1,84 @@
+package maps_test
+
+import (
+	mrand "math/rand"
+	"strings"
+	"testing"
+
+	"github.com/inlined/goldmine/pkg/maps"
+)
+
+func TestComplete(t *testing.T) {
+	s := `=3,6,8
+		  w...1.
+		  ..s...
+		  2d1..9`
+	r := maps.NewReader(strings.NewReader(s))
+	m, err := r.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		tag  string
+		path string
+		want string
+	}{
+		{
+			tag:  "detours for pickaxes",
+			path: "ul",
+			// The pickaxe is worth the detour on the way to the 9
+			want: "ulddrrrr",
+		}, {
+			tag:  "pads once nothing is in reach",
+			path: "",
+			want: "dlrrrrud",
+		}, {
+			tag:  "already full",
+			path: "rrrrrrrr",
+			want: "rrrrrrrr",
+		}, {
+			tag:  "invalid paths are padded",
+			path: "ull",
+			want: "ullrlrlr",
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			p := maps.ParsePath(test.path)
+			p.Complete(m)
+			if p.String() != test.want {
+				t.Errorf("Complete(%s) = %s; want %s", test.path, p, test.want)
+			}
+		})
+	}
+}
+
+func TestCompleteBeatsPad(t *testing.T) {
+	m, err := maps.Generate(mrand.New(mrand.NewSource(3)), maps.DefaultGenerateOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := maps.NewCompleter(m)
+	r := mrand.New(mrand.NewSource(3))
+	for trial := 0; trial < 50; trial++ {
+		var p maps.Path
+		v := m.PointsOfInterest[0]
+		for p.Len() < r.Intn(m.StepsAllowed) {
+			d := []maps.Direction{maps.Up, maps.Down, maps.Left, maps.Right}[r.Intn(4)]
+			if m.CanBeAt(v.Move(d)) {
+				v = v.Move(d)
+				p.Append(d)
+			}
+		}
+
+		padded := p.Copy()
+		padded.Pad(m)
+		completed := c.Complete(p)
+		if err := completed.Validate(m); err != nil {
+			t.Fatalf("Complete(%s) = %s is invalid: %s", p, completed, err)
+		}
+		if completed.Score(m) < padded.Score(m) {
+			t.Errorf("Complete(%s) scored %d; Pad scored %d", p, completed.Score(m), padded.Score(m))
+		}
+	}
+}
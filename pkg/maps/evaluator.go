@@ -0,0 +1,128 @@
+package maps
+
+// move remembers enough about an applied Direction to undo it
+type move struct {
+	from   Vertex
+	earned int
+	pickup bool
+}
+
+// Evaluator scores a path one move at a time. Moves can be undone, which
+// lets search based solvers score every prefix of a path in O(1) per step.
+// An Evaluator only allocates when it is created.
+type Evaluator struct {
+	m        Map
+	pos      Vertex
+	visits   []int
+	pickaxes uint
+	score    int
+	history  []move
+}
+
+// NewEvaluator creates an Evaluator standing at the start of m
+func NewEvaluator(m Map) *Evaluator {
+	e := &Evaluator{
+		m:       m,
+		pos:     m.PointsOfInterest[0],
+		visits:  make([]int, m.Rows()*m.Cols()),
+		history: make([]move, 0, m.StepsAllowed),
+	}
+	e.visits[e.index(e.pos)] = 1
+	return e
+}
+
+func (e *Evaluator) index(v Vertex) int {
+	return v.Row*e.m.Cols() + v.Col
+}
+
+// Apply moves in direction d and collects whatever is there if it
+// hasn't been collected yet. If d isn't a direction or would walk into
+// a wall or off the map, Apply does nothing and returns false.
+func (e *Evaluator) Apply(d Direction) bool {
+	v := e.pos.Move(d)
+	if v == e.pos || !e.m.CanBeAt(v) {
+		return false
+	}
+
+	mv := move{from: e.pos}
+	i := e.index(v)
+	e.visits[i]++
+	if e.visits[i] == 1 {
+		switch x := e.m.At(v); x {
+		case Space, Start:
+		case Pickaxe:
+			mv.pickup = true
+			e.pickaxes++
+		default:
+			mv.earned = int(x-'0') << e.pickaxes
+			e.score += mv.earned
+		}
+	}
+	e.pos = v
+	e.history = append(e.history, mv)
+	return true
+}
+
+// Undo reverts the most recent successful Apply. Returns false if
+// there is nothing to undo.
+func (e *Evaluator) Undo() bool {
+	if len(e.history) == 0 {
+		return false
+	}
+	mv := e.history[len(e.history)-1]
+	e.history = e.history[:len(e.history)-1]
+
+	e.visits[e.index(e.pos)]--
+	e.score -= mv.earned
+	if mv.pickup {
+		e.pickaxes--
+	}
+	e.pos = mv.from
+	return true
+}
+
+// Reset undoes every move, returning to the start of the map
+func (e *Evaluator) Reset() {
+	for e.Undo() {
+	}
+}
+
+// Evaluate scores p from the start of the map with the same rules as
+// Path.Score, except that bytes which aren't directions make p invalid.
+// The Evaluator is left at the end of p, or at the start of the map if
+// p is invalid.
+func (e *Evaluator) Evaluate(p Path) int {
+	e.Reset()
+	for _, d := range p {
+		if !e.Apply(d) {
+			e.Reset()
+			return 0
+		}
+	}
+	return e.score
+}
+
+// Score is the number of points earned by the moves applied so far
+func (e *Evaluator) Score() int {
+	return e.score
+}
+
+// Pickaxes is the number of pickaxes collected so far
+func (e *Evaluator) Pickaxes() int {
+	return int(e.pickaxes)
+}
+
+// Position is the current location on the map
+func (e *Evaluator) Position() Vertex {
+	return e.pos
+}
+
+// Len is the number of moves applied so far
+func (e *Evaluator) Len() int {
+	return len(e.history)
+}
+
+// Visited reports whether v has been walked on, including the start
+func (e *Evaluator) Visited(v Vertex) bool {
+	return e.visits[e.index(v)] != 0
+}
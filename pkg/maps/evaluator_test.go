@@ -0,0 +1,84 @@
+package maps_test
+
+import (
+	mrand "math/rand"
+	"strings"
+	"testing"
+
+	"github.com/inlined/goldmine/pkg/maps"
+)
+
+func TestEvaluator(t *testing.T) {
+	s := `=3,5,5
+ 	      w...1
+	      ..s..
+	      2d1..`
+	r := maps.NewReader(strings.NewReader(s))
+	m, err := r.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{"rrdlr", "rrull", "dlulr", "ldlurr", "dllur", "dllrr", "duull", "ddddd", "dxl"} {
+		t.Run(path, func(t *testing.T) {
+			p := maps.ParsePath(path)
+			e := maps.NewEvaluator(m)
+			want := p.Score(m)
+			if path == "dxl" {
+				want = 0 // Path.Score ignores bytes that aren't directions
+			}
+			if got := e.Evaluate(p); got != want {
+				t.Errorf("Evaluate(%s) = %d; Path.Score = %d", p, got, want)
+			}
+		})
+	}
+}
+
+func TestEvaluatorUndo(t *testing.T) {
+	m, err := maps.Generate(mrand.New(mrand.NewSource(7)), maps.GenerateOptions{
+		Rows:         8,
+		Cols:         8,
+		StepsAllowed: 30,
+		WallDensity:  0.2,
+		DigitDensity: 0.4,
+		Pickaxes:     4,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := mrand.New(mrand.NewSource(7))
+	e := maps.NewEvaluator(m)
+	dirs := []maps.Direction{maps.Up, maps.Down, maps.Left, maps.Right}
+	for trial := 0; trial < 100; trial++ {
+		var p maps.Path
+		var scores []int
+		for p.Len() < m.StepsAllowed {
+			d := dirs[r.Intn(len(dirs))]
+			if !e.Apply(d) {
+				continue
+			}
+			p.Append(d)
+			scores = append(scores, e.Score())
+			if want := p.Score(m); e.Score() != want {
+				t.Fatalf("after %s evaluator scored %d; Path.Score = %d", p, e.Score(), want)
+			}
+			if want := p.EndingVertex(m); e.Position() != want {
+				t.Fatalf("after %s evaluator is at %s; want %s", p, e.Position(), want)
+			}
+		}
+
+		// Unwind half way and make sure every prefix scores the same as before
+		for e.Len() > m.StepsAllowed/2 {
+			e.Undo()
+			p = p[:p.Len()-1]
+			if want := scores[p.Len()-1]; e.Score() != want {
+				t.Fatalf("undo to %s scored %d; want %d", p, e.Score(), want)
+			}
+		}
+		e.Reset()
+		if e.Score() != 0 || e.Pickaxes() != 0 || e.Position() != m.PointsOfInterest[0] || e.Len() != 0 {
+			t.Fatalf("Reset() left score %d, %d pickaxes, %d moves at %s", e.Score(), e.Pickaxes(), e.Len(), e.Position())
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package maps
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/inlined/rand"
+)
+
+// GenerateOptions are the knobs for creating random maps with Generate
+type GenerateOptions struct {
+	Rows         int
+	Cols         int
+	StepsAllowed int
+
+	// WallDensity is the fraction of cells that should be walls
+	WallDensity float64
+
+	// DigitDensity is the fraction of cells that should hold digits
+	DigitDensity float64
+
+	// DigitWeights is the relative frequency of each digit 0-9.
+	// All zeros means 1-9 are equally likely.
+	DigitWeights [10]int
+
+	// Pickaxes is the exact number of pickaxes to place
+	Pickaxes int
+}
+
+// DefaultGenerateOptions resembles the maps used in the Goldmine contest
+var DefaultGenerateOptions = GenerateOptions{
+	Rows:         40,
+	Cols:         40,
+	StepsAllowed: 40,
+	WallDensity:  0.2,
+	DigitDensity: 0.36,
+	Pickaxes:     64,
+}
+
+// uniform returns a random number in [0, 1)
+func uniform(r rand.Rand) float64 {
+	const precision = 1 << 30
+	return float64(r.Int31n(precision)) / precision
+}
+
+// Generate creates a random map. The map has exactly one start and every
+// point of interest can be reached from it; walls are knocked down as
+// needed to guarantee this.
+func Generate(r rand.Rand, opts GenerateOptions) (Map, error) {
+	var m Map
+	if opts.Rows < 1 || opts.Cols < 1 || opts.StepsAllowed < 1 {
+		return m, errors.New("Generate(): rows, columns, and steps must be positive")
+	}
+	if opts.WallDensity < 0 || opts.DigitDensity < 0 || opts.WallDensity+opts.DigitDensity > 1 {
+		return m, fmt.Errorf("Generate(): wall density %g and digit density %g must be a valid fraction of the map", opts.WallDensity, opts.DigitDensity)
+	}
+	cells := opts.Rows * opts.Cols
+	if opts.Pickaxes < 0 || opts.Pickaxes >= cells {
+		return m, fmt.Errorf("Generate(): cannot fit %d pickaxes in a %dx%d map", opts.Pickaxes, opts.Rows, opts.Cols)
+	}
+
+	weights := opts.DigitWeights
+	totalWeight := 0
+	for _, w := range weights {
+		if w < 0 {
+			return m, errors.New("Generate(): digit weights cannot be negative")
+		}
+		totalWeight += w
+	}
+	if totalWeight == 0 {
+		for i := 1; i < len(weights); i++ {
+			weights[i] = 1
+		}
+		totalWeight = len(weights) - 1
+	}
+
+	// Visit cells in a random order so that the start and pickaxes
+	// can be placed without collisions.
+	order := make([]int, cells)
+	for i := range order {
+		j := int(r.Int31n(int32(i + 1)))
+		order[i] = order[j]
+		order[j] = i
+	}
+
+	m.StepsAllowed = opts.StepsAllowed
+	m.Cells = make([][]byte, opts.Rows)
+	for row := range m.Cells {
+		m.Cells[row] = make([]byte, opts.Cols)
+	}
+	for i, cell := range order {
+		var val byte
+		switch {
+		case i == 0:
+			val = Start
+		case i <= opts.Pickaxes:
+			val = Pickaxe
+		default:
+			x := uniform(r)
+			if x < opts.WallDensity {
+				val = Wall
+				break
+			}
+			if x >= opts.WallDensity+opts.DigitDensity {
+				val = Space
+				break
+			}
+			pick := int(r.Int31n(int32(totalWeight)))
+			for digit, w := range weights {
+				if pick < w {
+					val = byte('0' + digit)
+					break
+				}
+				pick -= w
+			}
+		}
+		m.Cells[cell/opts.Cols][cell%opts.Cols] = val
+	}
+
+	if err := sanitizeCells(&m); err != nil {
+		return m, err
+	}
+	connect(m)
+	return m, nil
+}
+
+// connect knocks down walls so that every point of interest in m can
+// be reached from the start.
+func connect(m Map) {
+	start := m.PointsOfInterest[0]
+	dist := m.Distances(start)
+
+	// Dig an L shaped tunnel from each unreachable point to the start.
+	for _, v := range m.PointsOfInterest[1:] {
+		if dist[v.Row*m.Cols()+v.Col] >= 0 {
+			continue
+		}
+		for v != start {
+			switch {
+			case v.Row < start.Row:
+				v = v.Move(Down)
+			case v.Row > start.Row:
+				v = v.Move(Up)
+			case v.Col < start.Col:
+				v = v.Move(Right)
+			default:
+				v = v.Move(Left)
+			}
+			if m.At(v) == Wall {
+				m.Cells[v.Row][v.Col] = Space
+			}
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package maps_test
+
+import (
+	mrand "math/rand"
+	"strings"
+	"testing"
+
+	"github.com/inlined/goldmine/pkg/maps"
+)
+
+// reachable finds the cells that can be walked to from the start of m
+func reachable(m maps.Map) map[maps.Vertex]bool {
+	seen := map[maps.Vertex]bool{m.PointsOfInterest[0]: true}
+	queue := []maps.Vertex{m.PointsOfInterest[0]}
+	for len(queue) != 0 {
+		v := queue[0]
+		queue = queue[1:]
+		for _, d := range []maps.Direction{maps.Up, maps.Down, maps.Left, maps.Right} {
+			v2 := v.Move(d)
+			if m.CanBeAt(v2) && !seen[v2] {
+				seen[v2] = true
+				queue = append(queue, v2)
+			}
+		}
+	}
+	return seen
+}
+
+func TestGenerate(t *testing.T) {
+	for _, test := range []struct {
+		tag  string
+		opts maps.GenerateOptions
+		err  string
+	}{
+		{
+			tag:  "defaults",
+			opts: maps.DefaultGenerateOptions,
+		}, {
+			tag: "mostly walls",
+			opts: maps.GenerateOptions{
+				Rows:         12,
+				Cols:         9,
+				StepsAllowed: 20,
+				WallDensity:  0.9,
+				DigitDensity: 0.1,
+				Pickaxes:     5,
+			},
+		}, {
+			tag: "only nines",
+			opts: maps.GenerateOptions{
+				Rows:         5,
+				Cols:         5,
+				StepsAllowed: 10,
+				DigitDensity: 1,
+				DigitWeights: [10]int{9: 1},
+			},
+		}, {
+			tag: "too many pickaxes",
+			opts: maps.GenerateOptions{
+				Rows:         2,
+				Cols:         2,
+				StepsAllowed: 3,
+				Pickaxes:     4,
+			},
+			err: "Generate(): cannot fit 4 pickaxes in a 2x2 map",
+		}, {
+			tag: "overfull",
+			opts: maps.GenerateOptions{
+				Rows:         2,
+				Cols:         2,
+				StepsAllowed: 3,
+				WallDensity:  0.5,
+				DigitDensity: 0.6,
+			},
+			err: "Generate(): wall density 0.5 and digit density 0.6 must be a valid fraction of the map",
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			m, err := maps.Generate(mrand.New(mrand.NewSource(1)), test.opts)
+			if test.err != "" {
+				if err == nil || err.Error() != test.err {
+					t.Fatalf("maps.Generate(): expected err %s; got %v", test.err, err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("maps.Generate(): failed with err %s", err)
+			}
+
+			// Round tripping through the reader checks the format and that there is one start
+			r := maps.NewReader(strings.NewReader(m.String()))
+			parsed, err := r.Next()
+			if err != nil {
+				t.Fatalf("generated map could not be read: %s\n%s", err, m)
+			}
+			if parsed.Rows() != test.opts.Rows || parsed.Cols() != test.opts.Cols || parsed.StepsAllowed != test.opts.StepsAllowed {
+				t.Errorf("got a %dx%d map with %d steps; want %dx%d with %d", parsed.Rows(), parsed.Cols(), parsed.StepsAllowed, test.opts.Rows, test.opts.Cols, test.opts.StepsAllowed)
+			}
+
+			pickaxes := 0
+			seen := reachable(parsed)
+			for _, v := range parsed.PointsOfInterest {
+				if !seen[v] {
+					t.Errorf("point of interest %s cannot be reached\n%s", v, m)
+				}
+				switch x := parsed.At(v); {
+				case x == maps.Pickaxe:
+					pickaxes++
+				case x >= '0' && x <= '9' && test.opts.DigitWeights[x-'0'] == 0 && test.opts.DigitWeights != [10]int{}:
+					t.Errorf("found digit %c at %s which has no weight", x, v)
+				}
+			}
+			if pickaxes != test.opts.Pickaxes {
+				t.Errorf("got %d pickaxes; want %d", pickaxes, test.opts.Pickaxes)
+			}
+		})
+	}
+}
+
+func TestGenerateIsSeeded(t *testing.T) {
+	m1, err := maps.Generate(mrand.New(mrand.NewSource(42)), maps.DefaultGenerateOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m2, err := maps.Generate(mrand.New(mrand.NewSource(42)), maps.DefaultGenerateOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m1.String() != m2.String() {
+		t.Errorf("same seed generated different maps:\n%s\n%s", m1, m2)
+	}
+}
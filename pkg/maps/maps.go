@@ -286,7 +286,8 @@ func (p Path) EndingVertex(m Map) Vertex {
 }
 
 // Pad adds random moves to p that are valid according to m until
-// it has the required number of steps in m
+// it has the required number of steps in m. If p ends somewhere boxed
+// in by walls it is left as is, since any move would be invalid.
 func (p *Path) Pad(m Map) {
 	v := p.EndingVertex(m)
 	var d1, d2 Direction
@@ -299,11 +300,12 @@ func (p *Path) Pad(m Map) {
 	} else if m.CanBeAt(v.Move(Left)) {
 		d1 = Left
 		d2 = Right
-	} else {
+	} else if v == InvalidVertex || m.CanBeAt(v.Move(Right)) {
 		d1 = Right
 		d2 = Left
+	} else {
+		return
 	}
-	// Must be able to go one of the four directions
 
 	for p.Len() < m.StepsAllowed {
 		p.Append(d1)
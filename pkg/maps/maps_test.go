@@ -304,3 +304,19 @@ func TestPadding(t *testing.T) {
 		})
 	}
 }
+
+func TestPadBoxedIn(t *testing.T) {
+	r := maps.NewReader(strings.NewReader(`=3,3,4
+		.w.
+		wsw
+		.w9`))
+	m, err := r.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p maps.Path
+	p.Pad(m)
+	if p.Len() != 0 {
+		t.Errorf("Expected no moves from a boxed in start; got %s", p)
+	}
+}
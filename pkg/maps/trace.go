@@ -0,0 +1,57 @@
+package maps
+
+import "fmt"
+
+// TraceEvent describes what happened on one step of a path
+type TraceEvent struct {
+	Step      int
+	Direction Direction
+	Vertex    Vertex
+
+	// Cell is the map's contents at Vertex
+	Cell byte
+
+	// Revisit is true if Vertex was walked on earlier in the path, so
+	// nothing was collected
+	Revisit bool
+
+	// Pickaxes is the number of pickaxes held after the step
+	Pickaxes int
+
+	// Earned is the points collected on this step after the pickaxe
+	// multiplier
+	Earned int
+}
+
+func (e TraceEvent) String() string {
+	revisit := ""
+	if e.Revisit {
+		revisit = " (revisit)"
+	}
+	return fmt.Sprintf("%d %c %s %c pickaxes=%d earned=%d%s", e.Step, e.Direction, e.Vertex, e.Cell, e.Pickaxes, e.Earned, revisit)
+}
+
+// Trace follows p on m and explains every step. If a step is not a
+// legal move the trace stops before it; Validate explains why.
+func (p Path) Trace(m Map) []TraceEvent {
+	e := NewEvaluator(m)
+	events := make([]TraceEvent, 0, p.Len())
+	for i, d := range p {
+		v := e.Position().Move(d)
+		revisit := m.CanBeAt(v) && e.Visited(v)
+		before := e.Score()
+		if !e.Apply(d) {
+			break
+		}
+		events = append(events, TraceEvent{
+			Step:      i,
+			Direction: d,
+			Vertex:    v,
+			Cell:      m.At(v),
+			Revisit:   revisit,
+			Pickaxes:  e.Pickaxes(),
+			Earned:    e.Score() - before,
+		})
+	}
+	return events
+}
@@ -0,0 +1,61 @@
+package maps_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/goldmine/pkg/maps"
+)
+
+func TestTrace(t *testing.T) {
+	s := `=3,5,5
+ 	      w...1
+	      ..s..
+	      2d1..`
+	r := maps.NewReader(strings.NewReader(s))
+	m, err := r.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		tag    string
+		path   string
+		events []maps.TraceEvent
+	}{
+		{
+			tag:  "pickaxe mid points",
+			path: "dllrr",
+			events: []maps.TraceEvent{
+				{Step: 0, Direction: 'd', Vertex: maps.Vertex{2, 2}, Cell: '1', Earned: 1},
+				{Step: 1, Direction: 'l', Vertex: maps.Vertex{2, 1}, Cell: 'd', Pickaxes: 1},
+				{Step: 2, Direction: 'l', Vertex: maps.Vertex{2, 0}, Cell: '2', Pickaxes: 1, Earned: 4},
+				{Step: 3, Direction: 'r', Vertex: maps.Vertex{2, 1}, Cell: 'd', Pickaxes: 1, Revisit: true},
+				{Step: 4, Direction: 'r', Vertex: maps.Vertex{2, 2}, Cell: '1', Pickaxes: 1, Revisit: true},
+			},
+		}, {
+			tag:  "back to start",
+			path: "lr",
+			events: []maps.TraceEvent{
+				{Step: 0, Direction: 'l', Vertex: maps.Vertex{1, 1}, Cell: '.'},
+				{Step: 1, Direction: 'r', Vertex: maps.Vertex{1, 2}, Cell: 's', Revisit: true},
+			},
+		}, {
+			tag:  "stops at wall",
+			path: "ullll",
+			events: []maps.TraceEvent{
+				{Step: 0, Direction: 'u', Vertex: maps.Vertex{0, 2}, Cell: '.'},
+				{Step: 1, Direction: 'l', Vertex: maps.Vertex{0, 1}, Cell: '.'},
+			},
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			p := maps.ParsePath(test.path)
+			events := p.Trace(m)
+			if diff := cmp.Diff(events, test.events); diff != "" {
+				t.Errorf("Trace(%s) returned wrong events; diff=%s", p, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package maps
+
+import "fmt"
+
+// Reason explains why a path is invalid
+type Reason int
+
+const (
+	// HitWall means a step walked into a wall
+	HitWall Reason = iota + 1
+	// OutOfBounds means a step walked off the edge of the map
+	OutOfBounds
+	// UnknownDirection means a step was not one of u, d, l, or r
+	UnknownDirection
+	// TooLong means the path has more steps than the map allows
+	TooLong
+	// TooShort means the path has fewer steps than the map allows
+	TooShort
+)
+
+func (r Reason) String() string {
+	switch r {
+	case HitWall:
+		return "walked into a wall"
+	case OutOfBounds:
+		return "walked off the map"
+	case UnknownDirection:
+		return "unknown direction"
+	case TooLong:
+		return "too many steps"
+	case TooShort:
+		return "too few steps"
+	default:
+		return fmt.Sprintf("Reason(%d)", int(r))
+	}
+}
+
+// PathError describes the first problem found in a path
+type PathError struct {
+	// Step is the index of the offending step. For TooLong it is the
+	// first step past the limit and for TooShort it is the path's length.
+	Step int
+
+	// Vertex is where the path went wrong. For HitWall and OutOfBounds it
+	// is the vertex the step tried to move to; otherwise it is where the
+	// path was standing.
+	Vertex Vertex
+
+	// Direction is the offending byte for UnknownDirection, HitWall, and
+	// OutOfBounds steps.
+	Direction Direction
+
+	Reason Reason
+}
+
+func (e *PathError) Error() string {
+	switch e.Reason {
+	case UnknownDirection:
+		return fmt.Sprintf("step %d at %s: %s %q", e.Step, e.Vertex, e.Reason, e.Direction)
+	case HitWall, OutOfBounds:
+		return fmt.Sprintf("step %d (%c): %s at %s", e.Step, e.Direction, e.Reason, e.Vertex)
+	default:
+		return fmt.Sprintf("step %d at %s: %s", e.Step, e.Vertex, e.Reason)
+	}
+}
+
+// Validate walks p on m and returns a *PathError explaining the first
+// reason p isn't exactly m.StepsAllowed legal moves, or nil.
+func (p Path) Validate(m Map) error {
+	v := m.PointsOfInterest[0]
+	for i, d := range p {
+		if i == m.StepsAllowed {
+			return &PathError{Step: i, Vertex: v, Reason: TooLong}
+		}
+		switch d {
+		case Up, Down, Left, Right:
+		default:
+			return &PathError{Step: i, Vertex: v, Direction: d, Reason: UnknownDirection}
+		}
+
+		next := v.Move(d)
+		if next.Row < 0 || next.Col < 0 || next.Row >= m.Rows() || next.Col >= m.Cols() {
+			return &PathError{Step: i, Vertex: next, Direction: d, Reason: OutOfBounds}
+		}
+		if m.At(next) == Wall {
+			return &PathError{Step: i, Vertex: next, Direction: d, Reason: HitWall}
+		}
+		v = next
+	}
+
+	if p.Len() < m.StepsAllowed {
+		return &PathError{Step: p.Len(), Vertex: v, Reason: TooShort}
+	}
+	return nil
+}
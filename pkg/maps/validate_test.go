@@ -0,0 +1,79 @@
+package maps_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/goldmine/pkg/maps"
+)
+
+func TestValidate(t *testing.T) {
+	s := `=3,5,5
+ 	      w...1
+	      ..s..
+	      2d1..`
+	r := maps.NewReader(strings.NewReader(s))
+	m, err := r.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		tag  string
+		path string
+		err  *maps.PathError
+		msg  string
+	}{
+		{
+			tag:  "valid",
+			path: "rrdlr",
+		}, {
+			tag:  "walk on wall",
+			path: "duull",
+			err:  &maps.PathError{Step: 4, Vertex: maps.Vertex{0, 0}, Direction: maps.Left, Reason: maps.HitWall},
+			msg:  "step 4 (l): walked into a wall at (0, 0)",
+		}, {
+			tag:  "walk off world",
+			path: "ddddd",
+			err:  &maps.PathError{Step: 1, Vertex: maps.Vertex{3, 2}, Direction: maps.Down, Reason: maps.OutOfBounds},
+			msg:  "step 1 (d): walked off the map at (3, 2)",
+		}, {
+			tag:  "unknown direction",
+			path: "rrxll",
+			err:  &maps.PathError{Step: 2, Vertex: maps.Vertex{1, 4}, Direction: 'x', Reason: maps.UnknownDirection},
+			msg:  "step 2 at (1, 4): unknown direction 'x'",
+		}, {
+			tag:  "too long",
+			path: "rlrlrl",
+			err:  &maps.PathError{Step: 5, Vertex: maps.Vertex{1, 3}, Reason: maps.TooLong},
+			msg:  "step 5 at (1, 3): too many steps",
+		}, {
+			tag:  "too short",
+			path: "rl",
+			err:  &maps.PathError{Step: 2, Vertex: maps.Vertex{1, 2}, Reason: maps.TooShort},
+			msg:  "step 2 at (1, 2): too few steps",
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			err := maps.ParsePath(test.path).Validate(m)
+			if test.err == nil {
+				if err != nil {
+					t.Fatalf("Validate(%s): unexpected err %s", test.path, err)
+				}
+				return
+			}
+
+			got, ok := err.(*maps.PathError)
+			if !ok {
+				t.Fatalf("Validate(%s): expected a *maps.PathError; got %#v", test.path, err)
+			}
+			if diff := cmp.Diff(got, test.err); diff != "" {
+				t.Errorf("Validate(%s): wrong error; diff=%s", test.path, diff)
+			}
+			if got.Error() != test.msg {
+				t.Errorf("Validate(%s): got message %q; want %q", test.path, got.Error(), test.msg)
+			}
+		})
+	}
+}
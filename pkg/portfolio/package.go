@@ -0,0 +1,6 @@
+// Package portfolio races several registered solvers on the same map.
+// Different maps favour different strategies, so rather than guessing
+// which one to use, the portfolio runs them all concurrently and spends
+// more of its budget on whichever is improving fastest. Each round starts
+// by handing the best path so far to the members that are behind.
+package portfolio
@@ -0,0 +1,376 @@
+package portfolio
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/inlined/genetics"
+	"github.com/inlined/goldmine/pkg/maps"
+	"github.com/inlined/goldmine/pkg/solver"
+)
+
+func init() {
+	solver.DefaultRegistry.Register(solver.Descriptor{
+		Name:        "portfolio",
+		Description: "races several solvers concurrently, favouring whichever is improving",
+		Options: []solver.Option{{
+			Name:        "solvers",
+			Type:        solver.StringOption,
+			Default:     "bruteforce+graph",
+			Description: "solvers to race, separated by +",
+		}, {
+			Name:        "round",
+			Type:        solver.IntOption,
+			Default:     "10",
+			Description: "generations each solver gets per round, on average",
+		}},
+		New: func(i solver.Input, opts solver.Options) solver.Solver {
+			return &Solver{
+				Input: i,
+				specs: strings.Split(opts.String("solvers"), "+"),
+				round: opts.Int("round"),
+			}
+		},
+	})
+}
+
+// member is one of the raced solvers along with how well it is doing
+type member struct {
+	solver.Solver
+	// rate is a moving average of points gained per generation
+	rate float64
+	// share is how many generations the member gets this round, and taken
+	// how many of those it has run
+	share, taken int
+	// before is the member's score when the round started
+	before int
+	// ran counts the generations the member ran in its last Run
+	ran int
+}
+
+// Solver runs its members concurrently in rounds. Each round hands out
+// round generations per member, split in proportion to how quickly each
+// member has recently been improving, but never fewer than one. Every
+// generation a member runs counts as a generation of the Solver, so a
+// portfolio gets the same budget as any one of its members would. A round
+// can span several calls to Step or Run, and its generations are handed
+// out in the same order however it is split up.
+type Solver struct {
+	solver.Input
+	solver.Observers
+	specs []string
+	round int
+
+	members    []*member
+	generation int
+	best       solver.Incumbent
+	found      int
+	elapsed    time.Duration
+}
+
+// Init creates and initializes every member. Each member gets its own
+// Rand since they run concurrently.
+func (s *Solver) Init(popSize int) error {
+	if s.round < 1 {
+		return fmt.Errorf("portfolio.Solver.Init(): round must be positive, got %d", s.round)
+	}
+	s.members = nil
+
+	stats := solver.InitEvent{PointsOfInterest: len(s.Map.PointsOfInterest)}
+	for _, spec := range s.specs {
+		if name := strings.SplitN(spec, "(", 2)[0]; name == "portfolio" {
+			return fmt.Errorf("portfolio.Solver.Init(): a portfolio can't contain itself")
+		}
+		seed := int64(s.Rand.Int31n(1<<30))<<30 | int64(s.Rand.Int31n(1<<30))
+		input := s.Input
+		input.Rand = solver.NewRand(seed)
+		input.Seed = seed
+		sub, err := solver.DefaultRegistry.New(spec, input)
+		if err != nil {
+			return fmt.Errorf("portfolio.Solver.Init(): %s", err)
+		}
+
+		m := &member{Solver: sub}
+		sub.Subscribe(solver.ObserverFunc(func(e solver.Event) {
+			switch e := e.(type) {
+			case solver.GenerationEvent:
+				m.ran++
+			case solver.InitEvent:
+				if e.MeaningfulPaths > stats.MeaningfulPaths {
+					stats.MeaningfulPaths = e.MeaningfulPaths
+				}
+				stats.Genes += e.Genes
+				stats.PopulationSize += e.PopulationSize
+			}
+		}))
+		if err := sub.Init(popSize); err != nil {
+			return err
+		}
+		s.members = append(s.members, m)
+		s.best.Offer(m.Solver, m.Score())
+	}
+	if len(s.members) == 0 {
+		return fmt.Errorf("portfolio.Solver.Init(): no solvers to race")
+	}
+
+	s.Notify(stats)
+	s.best.Improved()
+	return nil
+}
+
+// allocate starts a round by handing the best path so far to every member
+// that hasn't found as good a one, then splitting the round's generations
+// among the members in proportion to how quickly each has recently been
+// improving. Every member gets at least one, and the fastest members get
+// what's left over from rounding.
+func (s *Solver) allocate() {
+	if c := s.Best(); c.Species != nil {
+		best := s.Path(c)
+		for _, m := range s.members {
+			if a, ok := m.Solver.(solver.Adopter); ok && m.Score() < s.Score() {
+				a.Adopt(best)
+			}
+		}
+	}
+
+	fastest := make([]*member, len(s.members))
+	copy(fastest, s.members)
+	sort.SliceStable(fastest, func(i, j int) bool {
+		return fastest[i].rate > fastest[j].rate
+	})
+	sum := 0.0
+	for _, m := range fastest {
+		sum += m.rate
+	}
+	spare := (s.round - 1) * len(fastest)
+	left := spare
+	for _, m := range fastest {
+		m.share = 1 + spare/len(fastest)
+		if sum != 0 {
+			m.share = 1 + int(float64(spare)*m.rate/sum)
+		}
+		m.taken, m.before = 0, m.Score()
+		left -= m.share - 1
+	}
+	for i := 0; left > 0; i = (i + 1) % len(fastest) {
+		fastest[i].share++
+		left--
+	}
+}
+
+// schedule decides how many of the round's next total generations each
+// member runs. The round hands them out a generation per member at a time
+// in order, so splitting it into more calls doesn't change who runs what.
+func (s *Solver) schedule(total int) []int {
+	counts := make([]int, len(s.members))
+	for ; total > 0; total-- {
+		next := -1
+		for i, m := range s.members {
+			if m.taken+counts[i] < m.share && (next == -1 || m.taken+counts[i] < s.members[next].taken+counts[next]) {
+				next = i
+			}
+		}
+		if next == -1 {
+			break
+		}
+		counts[next]++
+	}
+	return counts
+}
+
+// Step runs count generations
+func (s *Solver) Step(count int) {
+	s.evolve(context.Background(), count)
+}
+
+// Run races the members for up to generations generations, or until ctx
+// is done if generations is 0. Stops early if a member proves its path is
+// optimal.
+func (s *Solver) Run(ctx context.Context, generations int) error {
+	err := s.evolve(ctx, generations)
+	s.Notify(solver.DoneEvent{Generation: s.generation, Score: s.Score(), Err: err})
+	return err
+}
+
+// evolve runs what's left of each round until generations generations
+// are done
+func (s *Solver) evolve(ctx context.Context, generations int) error {
+	for done := 0; !s.Optimal() && (generations == 0 || done < generations); {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		left := 0
+		for _, m := range s.members {
+			left += m.share - m.taken
+		}
+		if left == 0 {
+			s.allocate()
+			left = s.round * len(s.members)
+		}
+		if generations != 0 && generations-done < left {
+			left = generations - done
+		}
+		ran, err := s.runMembers(ctx, s.schedule(left))
+		done += ran
+		if err != nil || ran == 0 {
+			return err
+		}
+	}
+	return nil
+}
+
+// runMembers runs every member concurrently for its count of generations
+// and reports each generation they ran. Returns how many that was, which
+// is fewer than asked for if ctx is done or a member proves its path
+// optimal.
+func (s *Solver) runMembers(ctx context.Context, counts []int) (int, error) {
+	start := time.Now()
+	defer func() {
+		s.elapsed += time.Since(start)
+	}()
+	errs := make([]error, len(s.members))
+	var wg sync.WaitGroup
+	for i, m := range s.members {
+		m.ran = 0
+		if counts[i] == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, m *member) {
+			defer wg.Done()
+			errs[i] = m.Run(ctx, counts[i])
+		}(i, m)
+	}
+	wg.Wait()
+	for _, m := range s.members {
+		s.best.Offer(m.Solver, m.Score())
+	}
+
+	// Members run concurrently, so every generation they ran is reported
+	// with how the members stand at the end of them
+	ran, over := 0, true
+	e := solver.GenerationEvent{Best: s.Score(), Worst: s.Score()}
+	for _, m := range s.members {
+		m.taken += m.ran
+		ran += m.ran
+		if m.taken < m.share {
+			over = false
+		}
+		e.Mean += float64(m.Score()) / float64(len(s.members))
+		if m.Score() < e.Worst {
+			e.Worst = m.Score()
+		}
+	}
+	if over {
+		for _, m := range s.members {
+			if m.taken != 0 {
+				gain := float64(m.Score()-m.before) / float64(m.taken)
+				m.rate = (m.rate + gain) / 2
+			}
+		}
+	}
+	improved := s.best.Improved()
+	for g := 0; g < ran; g++ {
+		if g == ran-1 && improved {
+			s.found = s.generation
+			if s.Observed() {
+				s.Notify(solver.ImprovementEvent{Generation: s.generation, Score: s.Score(), Path: s.Path(s.Best())})
+			}
+		}
+		e.Generation = s.generation
+		s.Notify(e)
+		s.generation++
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			return ran, err
+		}
+	}
+	return ran, nil
+}
+
+// Path asks whichever member made c to translate it
+func (s *Solver) Path(c genetics.Chromosome) maps.Path {
+	return s.best.Path(c)
+}
+
+// Score is the best score of any member
+func (s *Solver) Score() int {
+	return s.best.Score()
+}
+
+// Best is the best chromosome of any member
+func (s *Solver) Best() genetics.Chromosome {
+	return s.best.Best()
+}
+
+// Result reports the overall best path along with the work of every member
+func (s *Solver) Result() solver.Result {
+	r := solver.Result{
+		Score:       s.Score(),
+		Optimal:     s.Optimal(),
+		Generations: s.generation,
+		Elapsed:     s.elapsed,
+		FoundAt:     s.found,
+	}
+	if c := s.Best(); c.Species != nil {
+		r.Path = s.Path(c)
+	}
+	hall := solver.HallOfFame{Size: s.Alternatives}
+	for _, m := range s.members {
+		mr := m.Result()
+		r.Evaluations += mr.Evaluations
+		hall.Merge(mr.Alternatives)
+		if mr.UpperBound != 0 && (r.UpperBound == 0 || mr.UpperBound < r.UpperBound) {
+			r.UpperBound = mr.UpperBound
+		}
+	}
+	r.Alternatives = hall.Entries()
+	return r
+}
+
+// Optimal reports whether any member has proven its path optimal
+func (s *Solver) Optimal() bool {
+	for _, m := range s.members {
+		if o, ok := m.Solver.(interface{ Optimal() bool }); ok && o.Optimal() {
+			return true
+		}
+	}
+	return false
+}
+
+// Checkpoint saves every member
+func (s *Solver) Checkpoint() solver.Checkpoint {
+	cp := solver.Checkpoint{
+		Score:      s.Score(),
+		Generation: s.generation,
+		FoundAt:    s.found,
+		Seed:       s.Seed,
+	}
+	for _, m := range s.members {
+		cp.Members = append(cp.Members, m.Checkpoint())
+	}
+	return cp
+}
+
+// Restore resumes every member from a Checkpoint made with the same solvers
+func (s *Solver) Restore(cp solver.Checkpoint) error {
+	if len(cp.Members) != len(s.members) {
+		return fmt.Errorf("portfolio.Solver.Restore(): checkpoint has %d solvers but the portfolio has %d", len(cp.Members), len(s.members))
+	}
+	for i, m := range s.members {
+		if err := m.Restore(cp.Members[i]); err != nil {
+			return err
+		}
+		s.best.Offer(m.Solver, m.Score())
+	}
+	s.generation = cp.Generation
+	s.found = cp.FoundAt
+	s.best.Improved()
+	return nil
+}
@@ -0,0 +1,151 @@
+package portfolio_test
+
+import (
+	"context"
+	mrand "math/rand"
+	"strings"
+	"testing"
+
+	"github.com/inlined/genetics"
+	"github.com/inlined/goldmine/pkg/maps"
+	"github.com/inlined/goldmine/pkg/solver"
+
+	_ "github.com/inlined/goldmine/pkg/bruteforce"
+	_ "github.com/inlined/goldmine/pkg/exact"
+	_ "github.com/inlined/goldmine/pkg/graph"
+	_ "github.com/inlined/goldmine/pkg/portfolio"
+)
+
+const testMap = `=4,5,8
+	w...1
+	..s.9
+	2d1..
+	.w..3`
+
+func newSolver(t *testing.T, spec string) (solver.Solver, maps.Map) {
+	r := maps.NewReader(strings.NewReader(testMap))
+	m, err := r.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The zero flags give the command line's defaults
+	var selection genetics.NaturalSelectionFlag
+	var crossover genetics.CrossoverFlag
+	var mutation genetics.MutationFlag
+	s, err := solver.DefaultRegistry.New(spec, solver.Input{
+		Map: m,
+		Evolver: genetics.Evolver{
+			ReplacementCount: 5,
+			MutationRate:     0.1,
+			Selector:         selection.Get(),
+			Crossover:        crossover.Get(),
+			Mutator:          mutation.Get(),
+		},
+		Rand: mrand.New(mrand.NewSource(1)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Init(10); err != nil {
+		t.Fatal(err)
+	}
+	return s, m
+}
+
+func TestPortfolio(t *testing.T) {
+	s, m := newSolver(t, "portfolio(solvers=bruteforce+graph+exact)")
+	if err := s.Run(context.Background(), 100); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only exact can finish, so the portfolio should stop once it does
+	if !s.(interface{ Optimal() bool }).Optimal() {
+		t.Error("portfolio did not notice exact proved its path optimal")
+	}
+	p := s.Path(s.Best())
+	if got := p.Score(m); got != s.Score() {
+		t.Errorf("Path(Best()) scores %d; Score() is %d", got, s.Score())
+	}
+}
+
+func TestBudget(t *testing.T) {
+	s, _ := newSolver(t, "portfolio(solvers=bruteforce+graph,round=4)")
+	reported := 0
+	s.Subscribe(solver.ObserverFunc(func(e solver.Event) {
+		if _, ok := e.(solver.GenerationEvent); ok {
+			reported++
+		}
+	}))
+	if err := s.Run(context.Background(), 25); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every generation of a member is one of the portfolio's
+	ran := 0
+	for _, cp := range s.Checkpoint().Members {
+		ran += cp.Generation
+	}
+	if ran != 25 || reported != 25 || s.Result().Generations != 25 {
+		t.Errorf("members ran %d generations, the portfolio reported %d and counted %d; want 25", ran, reported, s.Result().Generations)
+	}
+}
+
+func TestShareBest(t *testing.T) {
+	s, _ := newSolver(t, "portfolio(solvers=graph+bruteforce,round=1)")
+	// A round of one generation each, then the next round starts by
+	// sharing the best path before graph runs again
+	s.Step(2)
+	before := s.Checkpoint().Members
+	best := s.Score()
+	if before[1].Score >= best {
+		t.Fatalf("bruteforce scored %d after a generation, as well as graph's %d; want it behind", before[1].Score, best)
+	}
+	s.Step(1)
+	after := s.Checkpoint().Members
+	if after[1].Generation != before[1].Generation {
+		t.Fatalf("bruteforce ran %d generations; want it to wait its turn", after[1].Generation-before[1].Generation)
+	}
+	if after[1].Score != best {
+		t.Errorf("bruteforce scores %d after the best path was shared; want %d", after[1].Score, best)
+	}
+}
+
+func TestCheckpoint(t *testing.T) {
+	s, _ := newSolver(t, "portfolio")
+	s.Step(3)
+	cp := s.Checkpoint()
+	if len(cp.Members) != 2 {
+		t.Fatalf("portfolio checkpointed %d members; want 2", len(cp.Members))
+	}
+
+	restored, _ := newSolver(t, "portfolio")
+	if err := restored.Restore(cp); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Score() != s.Score() {
+		t.Errorf("restored portfolio scores %d; want %d", restored.Score(), s.Score())
+	}
+
+	other, _ := newSolver(t, "portfolio(solvers=graph)")
+	if err := other.Restore(cp); err == nil {
+		t.Error("Restore() accepted a checkpoint with the wrong number of solvers")
+	}
+}
+
+func TestBadSolvers(t *testing.T) {
+	for _, spec := range []string{
+		"portfolio(solvers=missing)",
+		"portfolio(solvers=portfolio)",
+		"portfolio(round=0)",
+	} {
+		t.Run(spec, func(t *testing.T) {
+			s, err := solver.DefaultRegistry.New(spec, solver.Input{Rand: mrand.New(mrand.NewSource(1))})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := s.Init(10); err == nil {
+				t.Errorf("Init() accepted %s", spec)
+			}
+		})
+	}
+}
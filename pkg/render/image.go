@@ -0,0 +1,65 @@
+package render
+
+import (
+	"image/color"
+
+	"github.com/inlined/goldmine/pkg/maps"
+)
+
+// cellSize is the width and height in pixels of one map cell in images
+const cellSize = 24
+
+var (
+	wallColor    = color.RGBA{0x40, 0x40, 0x40, 0xff}
+	spaceColor   = color.RGBA{0xf4, 0xf0, 0xe6, 0xff}
+	startColor   = color.RGBA{0x4c, 0xaf, 0x50, 0xff}
+	pickaxeColor = color.RGBA{0x7e, 0x57, 0xc2, 0xff}
+	gridColor    = color.RGBA{0xd0, 0xc8, 0xb8, 0xff}
+	pathColor    = color.RGBA{0xe5, 0x39, 0x35, 0xff}
+	textColor    = color.RGBA{0x21, 0x21, 0x21, 0xff}
+	orderColor   = color.RGBA{0x0d, 0x47, 0xa1, 0xff}
+)
+
+// cellColor picks the fill for a cell. Digits are shades of gold that
+// darken as they become more valuable.
+func cellColor(x byte) color.RGBA {
+	switch x {
+	case maps.Wall:
+		return wallColor
+	case maps.Start:
+		return startColor
+	case maps.Pickaxe:
+		return pickaxeColor
+	case maps.Space:
+		return spaceColor
+	default:
+		shade := uint8(x-'0') * 12
+		return color.RGBA{0xff, 0xe0 - shade, 0x82 - shade, 0xff}
+	}
+}
+
+// pickup is a point of interest collected by a path
+type pickup struct {
+	maps.Vertex
+	// Order counts collections starting at 1
+	Order int
+}
+
+// pickups lists the points of interest p collects on m in the order
+// they were collected, along with every vertex p walks through.
+func pickups(m maps.Map, p maps.Path) ([]pickup, []maps.Vertex) {
+	var collected []pickup
+	walk := []maps.Vertex{m.PointsOfInterest[0]}
+	for _, e := range p.Trace(m) {
+		walk = append(walk, e.Vertex)
+		if !e.Revisit && (e.Earned != 0 || e.Cell == maps.Pickaxe) {
+			collected = append(collected, pickup{Vertex: e.Vertex, Order: len(collected) + 1})
+		}
+	}
+	return collected, walk
+}
+
+// center is the pixel in the middle of a cell
+func center(v maps.Vertex) (int, int) {
+	return v.Col*cellSize + cellSize/2, v.Row*cellSize + cellSize/2
+}
@@ -0,0 +1,76 @@
+package render_test
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/inlined/goldmine/pkg/maps"
+	"github.com/inlined/goldmine/pkg/render"
+)
+
+func testMap(t *testing.T) maps.Map {
+	s := `=3,5,5
+ 	      w...1
+	      ..s..
+	      2d1..`
+	r := maps.NewReader(strings.NewReader(s))
+	m, err := r.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestPNG(t *testing.T) {
+	m := testMap(t)
+	var b bytes.Buffer
+	if err := render.PNG(&b, m, maps.ParsePath("dllur")); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&b)
+	if err != nil {
+		t.Fatalf("render.PNG() wrote an unreadable image: %s", err)
+	}
+
+	bounds := img.Bounds()
+	if bounds.Dx() != 5*24 || bounds.Dy() != 3*24 {
+		t.Errorf("expected a 120x72 image; got %dx%d", bounds.Dx(), bounds.Dy())
+	}
+
+	// Away from the path and digits, each cell is filled with its own color
+	wall := img.At(2, 20)
+	space := img.At(24+2, 20)
+	if wall == space {
+		t.Errorf("walls and spaces are both drawn as %v", wall)
+	}
+	// The path runs between the centers of (1, 2) and (2, 2)
+	path := img.At(2*24+12, 24+18)
+	if path == space {
+		t.Errorf("path was not drawn; got %v", path)
+	}
+}
+
+func TestSVG(t *testing.T) {
+	m := testMap(t)
+	var b bytes.Buffer
+	if err := render.SVG(&b, m, maps.ParsePath("dllur")); err != nil {
+		t.Fatal(err)
+	}
+	s := b.String()
+
+	for _, want := range []string{
+		`<svg xmlns="http://www.w3.org/2000/svg" width="120" height="72"`,
+		`points="60,36 60,60 36,60 12,60 12,36 36,36"`,
+		// The 2 at (2, 0) is the third point of interest collected
+		`<text x="2" y="50" font-family="sans-serif" font-size="8" dominant-baseline="hanging" fill="#0d47a1">3</text>`,
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("render.SVG() is missing %s:\n%s", want, s)
+		}
+	}
+	if !strings.HasSuffix(s, "</svg>\n") {
+		t.Errorf("render.SVG() did not close the document")
+	}
+}
@@ -0,0 +1,3 @@
+// Package render draws maps with a path overlaid so that solutions
+// can be inspected by eye.
+package render
@@ -0,0 +1,106 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strconv"
+
+	"github.com/inlined/goldmine/pkg/maps"
+)
+
+// glyphs is a 3x5 pixel font for the digits 0-9. Each row is three bits,
+// most significant on the left.
+var glyphs = [10][5]uint8{
+	{7, 5, 5, 5, 7},
+	{2, 6, 2, 2, 7},
+	{7, 1, 7, 4, 7},
+	{7, 1, 7, 1, 7},
+	{5, 5, 7, 1, 1},
+	{7, 4, 7, 1, 7},
+	{7, 4, 7, 5, 7},
+	{7, 1, 1, 1, 1},
+	{7, 5, 7, 5, 7},
+	{7, 5, 7, 1, 7},
+}
+
+func fill(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	r = r.Intersect(img.Bounds())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
+
+// text draws the digits in s with their top left corner at x, y and
+// each font pixel scaled to a scale x scale square.
+func text(img *image.RGBA, x, y, scale int, s string, c color.RGBA) {
+	for _, r := range s {
+		g := glyphs[r-'0']
+		for row, bits := range g {
+			for col := 0; col < 3; col++ {
+				if bits&(4>>uint(col)) == 0 {
+					continue
+				}
+				px := x + col*scale
+				py := y + row*scale
+				fill(img, image.Rect(px, py, px+scale, py+scale), c)
+			}
+		}
+		x += 4 * scale
+	}
+}
+
+// line draws a width x width brush from x0, y0 to x1, y1. Paths only
+// move horizontally or vertically between cell centers.
+func line(img *image.RGBA, x0, y0, x1, y1, width int, c color.RGBA) {
+	if x0 > x1 {
+		x0, x1 = x1, x0
+	}
+	if y0 > y1 {
+		y0, y1 = y1, y0
+	}
+	half := width / 2
+	fill(img, image.Rect(x0-half, y0-half, x1-half+width, y1-half+width), c)
+}
+
+// PNG draws m with p overlaid as a PNG image. Each cell is colored by
+// type with digits drawn on top, the path is a line through the cells it
+// walks, and points of interest are numbered in the order collected.
+func PNG(w io.Writer, m maps.Map, p maps.Path) error {
+	img := image.NewRGBA(image.Rect(0, 0, m.Cols()*cellSize, m.Rows()*cellSize))
+	for row := 0; row < m.Rows(); row++ {
+		for col := 0; col < m.Cols(); col++ {
+			x := m.At(maps.Vertex{Row: row, Col: col})
+			r := image.Rect(col*cellSize, row*cellSize, (col+1)*cellSize, (row+1)*cellSize)
+			fill(img, r, gridColor)
+			fill(img, r.Inset(1), cellColor(x))
+			if x >= '0' && x <= '9' {
+				const scale = 3
+				text(img, r.Min.X+(cellSize-3*scale)/2, r.Min.Y+(cellSize-5*scale)/2, scale, string(x), textColor)
+			}
+		}
+	}
+
+	collected, walk := pickups(m, p)
+	for i := 1; i < len(walk); i++ {
+		x0, y0 := center(walk[i-1])
+		x1, y1 := center(walk[i])
+		line(img, x0, y0, x1, y1, 3, pathColor)
+	}
+	for _, c := range collected {
+		s := strconv.Itoa(c.Order)
+		x := c.Col*cellSize + 2
+		y := c.Row*cellSize + 2
+		fill(img, image.Rect(x-1, y-1, x+4*len(s), y+6), spaceColor)
+		text(img, x, y, 1, s, orderColor)
+	}
+	if len(walk) > 1 {
+		x, y := center(walk[len(walk)-1])
+		fill(img, image.Rect(x-4, y-4, x+4, y+4), pathColor)
+	}
+
+	return png.Encode(w, img)
+}
@@ -0,0 +1,58 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+
+	"github.com/inlined/goldmine/pkg/maps"
+)
+
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// SVG draws m with p overlaid as an SVG document with the same layout
+// as PNG.
+func SVG(w io.Writer, m maps.Map, p maps.Path) error {
+	b := bufio.NewWriter(w)
+	width, height := m.Cols()*cellSize, m.Rows()*cellSize
+	fmt.Fprintf(b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n", width, height, width, height)
+	fmt.Fprintf(b, "<rect width=\"%d\" height=\"%d\" fill=\"%s\"/>\n", width, height, hex(gridColor))
+
+	for row := 0; row < m.Rows(); row++ {
+		for col := 0; col < m.Cols(); col++ {
+			x := m.At(maps.Vertex{Row: row, Col: col})
+			fmt.Fprintf(b, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\"/>\n",
+				col*cellSize+1, row*cellSize+1, cellSize-2, cellSize-2, hex(cellColor(x)))
+			if x >= '0' && x <= '9' {
+				cx, cy := center(maps.Vertex{Row: row, Col: col})
+				fmt.Fprintf(b, "<text x=\"%d\" y=\"%d\" font-family=\"monospace\" font-size=\"16\" text-anchor=\"middle\" dominant-baseline=\"central\" fill=\"%s\">%c</text>\n",
+					cx, cy, hex(textColor), x)
+			}
+		}
+	}
+
+	collected, walk := pickups(m, p)
+	if len(walk) > 1 {
+		b.WriteString("<polyline fill=\"none\" stroke-width=\"3\" stroke-linejoin=\"round\" stroke=\"" + hex(pathColor) + "\" points=\"")
+		for i, v := range walk {
+			if i != 0 {
+				b.WriteByte(' ')
+			}
+			x, y := center(v)
+			fmt.Fprintf(b, "%d,%d", x, y)
+		}
+		b.WriteString("\"/>\n")
+		x, y := center(walk[len(walk)-1])
+		fmt.Fprintf(b, "<circle cx=\"%d\" cy=\"%d\" r=\"4\" fill=\"%s\"/>\n", x, y, hex(pathColor))
+	}
+	for _, c := range collected {
+		fmt.Fprintf(b, "<text x=\"%d\" y=\"%d\" font-family=\"sans-serif\" font-size=\"8\" dominant-baseline=\"hanging\" fill=\"%s\">%d</text>\n",
+			c.Col*cellSize+2, c.Row*cellSize+2, hex(orderColor), c.Order)
+	}
+
+	b.WriteString("</svg>\n")
+	return b.Flush()
+}
@@ -0,0 +1,112 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/inlined/goldmine/pkg/maps"
+)
+
+// ANSI escape sequences used when Options.Color is set
+const (
+	reset     = "\x1b[0m"
+	dim       = "\x1b[2m"
+	walked    = "\x1b[44m"
+	collected = "\x1b[1;33;44m"
+	pickaxe   = "\x1b[1;36;44m"
+	end       = "\x1b[1;37;41m"
+)
+
+// Marks drawn after each cell when Options.Color is not set
+const (
+	markNone      = ' '
+	markWalked    = '*'
+	markCollected = '+'
+	markEnd       = '@'
+)
+
+// Options controls how a map is drawn
+type Options struct {
+	// Color uses ANSI escape codes to highlight the path. Otherwise each
+	// cell is followed by a mark: '*' when walked, '+' when something was
+	// collected there, and '@' at the end of the path.
+	Color bool
+}
+
+// overlay summarizes what a path did to each cell of its map
+type overlay struct {
+	walked    []bool
+	collected []bool
+	end       maps.Vertex
+}
+
+func newOverlay(m maps.Map, p maps.Path) overlay {
+	o := overlay{
+		walked:    make([]bool, m.Rows()*m.Cols()),
+		collected: make([]bool, m.Rows()*m.Cols()),
+	}
+	collected, walk := pickups(m, p)
+	for _, v := range walk {
+		o.walked[v.Row*m.Cols()+v.Col] = true
+	}
+	for _, c := range collected {
+		o.collected[c.Row*m.Cols()+c.Col] = true
+	}
+	o.end = walk[len(walk)-1]
+	return o
+}
+
+// Text draws m with p overlaid. The first line summarizes the score
+// and, if p is invalid, why.
+func Text(w io.Writer, m maps.Map, p maps.Path, opts Options) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "score=%d steps=%d/%d", p.Score(m), p.Len(), m.StepsAllowed)
+	if err := p.Validate(m); err != nil {
+		fmt.Fprintf(b, " (%s)", err)
+	}
+	b.WriteByte('\n')
+
+	o := newOverlay(m, p)
+	for row := 0; row < m.Rows(); row++ {
+		for col := 0; col < m.Cols(); col++ {
+			v := maps.Vertex{Row: row, Col: col}
+			i := row*m.Cols() + col
+			x := m.At(v)
+			if !opts.Color {
+				mark := byte(markNone)
+				switch {
+				case v == o.end:
+					mark = markEnd
+				case o.collected[i]:
+					mark = markCollected
+				case o.walked[i]:
+					mark = markWalked
+				}
+				b.WriteByte(x)
+				b.WriteByte(mark)
+				continue
+			}
+
+			switch {
+			case v == o.end:
+				b.WriteString(end)
+			case o.collected[i] && x == maps.Pickaxe:
+				b.WriteString(pickaxe)
+			case o.collected[i]:
+				b.WriteString(collected)
+			case o.walked[i]:
+				b.WriteString(walked)
+			case x == maps.Wall:
+				b.WriteString(dim)
+			default:
+				b.WriteByte(x)
+				continue
+			}
+			b.WriteByte(x)
+			b.WriteString(reset)
+		}
+		b.WriteByte('\n')
+	}
+	return b.Flush()
+}
@@ -0,0 +1,64 @@
+package render_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/goldmine/pkg/maps"
+	"github.com/inlined/goldmine/pkg/render"
+)
+
+func TestText(t *testing.T) {
+	s := `=3,5,5
+ 	      w...1
+	      ..s..
+	      2d1..`
+	r := maps.NewReader(strings.NewReader(s))
+	m, err := r.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		tag   string
+		path  string
+		color bool
+		want  string
+	}{
+		{
+			tag:  "plain",
+			path: "dllur",
+			want: "score=5 steps=5/5\n" +
+				"w . . . 1 \n" +
+				".*.@s*. . \n" +
+				"2+d+1+. . \n",
+		}, {
+			tag:  "invalid",
+			path: "rrr",
+			want: "score=0 steps=3/5 (step 2 (r): walked off the map at (1, 5))\n" +
+				"w . . . 1 \n" +
+				". . s*.*.@\n" +
+				"2 d 1 . . \n",
+		}, {
+			tag:   "color",
+			path:  "ur",
+			color: true,
+			want: "score=0 steps=2/5 (step 2 at (0, 3): too few steps)\n" +
+				"\x1b[2mw\x1b[0m.\x1b[44m.\x1b[0m\x1b[1;37;41m.\x1b[0m1\n" +
+				"..\x1b[44ms\x1b[0m..\n" +
+				"2d1..\n",
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := render.Text(&b, m, maps.ParsePath(test.path), render.Options{Color: test.color}); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(b.String(), test.want); diff != "" {
+				t.Errorf("render.Text(%s) drew:\n%s\nwant:\n%s\ndiff=%s", test.path, b.String(), test.want, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+// Package schedule solves many maps at once. A Pool of workers runs
+// slices of generations on each map's solver concurrently, and a
+// Scheduler decides which map deserves the next slice so that compute
+// goes where it earns the most.
+package schedule
@@ -0,0 +1,160 @@
+package schedule
+
+import (
+	"context"
+	"sync"
+
+	"github.com/inlined/rand"
+
+	"github.com/inlined/goldmine/pkg/solver"
+)
+
+// Job is a map's Solver and how much it may run
+type Job struct {
+	Solver solver.Solver
+
+	// Generations is the most generations to run; 0 for no limit
+	Generations int
+
+	// Complete Jobs are started but never run, such as maps that were
+	// already finished when resumed from a checkpoint.
+	Complete bool
+}
+
+// Pool runs Jobs concurrently, a slice of generations at a time
+type Pool struct {
+	Workers   int
+	Slice     int
+	Scheduler Scheduler
+
+	// Rand is used by the Scheduler. Results only depend on it if Jobs
+	// have no generation limit and the Pool is stopped by ctx.
+	Rand rand.Rand
+
+	// Start is called from a worker before a Job first runs, typically
+	// to Init its Solver. Every Job is started exactly once.
+	Start func(i int)
+}
+
+// Run works on jobs until every Job has used its generations, proved
+// itself optimal, or ctx is done. Returns the Progress of every Job.
+func (p *Pool) Run(ctx context.Context, jobs []Job) []Progress {
+	progress := make([]Progress, len(jobs))
+	started := make([]bool, len(jobs))
+	busy := make([]bool, len(jobs))
+	finished := make([]bool, len(jobs))
+	left := len(jobs)
+
+	// ran counts the generations each Solver reports. Only the worker
+	// running a Job touches its count.
+	ran := make([]int, len(jobs))
+	for i, job := range jobs {
+		i := i
+		job.Solver.Subscribe(solver.ObserverFunc(func(e solver.Event) {
+			if _, ok := e.(solver.GenerationEvent); ok {
+				ran[i]++
+			}
+		}))
+	}
+
+	var mu sync.Mutex
+	cond := sync.NewCond(&mu)
+
+	// Wake up idle workers when ctx is done
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			mu.Lock()
+			cond.Broadcast()
+			mu.Unlock()
+		case <-stop:
+		}
+	}()
+
+	// next blocks until there is a Job to work on. Returns false once
+	// there is nothing left to do.
+	next := func() (int, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		for {
+			// Unstarted Jobs are started even if ctx is done so that
+			// every Solver can report its best path.
+			var candidates []int
+			for i := range jobs {
+				if !busy[i] && !finished[i] && (!started[i] || ctx.Err() == nil) {
+					candidates = append(candidates, i)
+				}
+			}
+			if len(candidates) != 0 {
+				i := p.Scheduler.Pick(p.Rand, candidates, progress)
+				busy[i] = true
+				return i, true
+			}
+			if left == 0 || ctx.Err() != nil {
+				return 0, false
+			}
+			cond.Wait()
+		}
+	}
+
+	work := func() {
+		for {
+			i, ok := next()
+			if !ok {
+				return
+			}
+			job := jobs[i]
+			if !started[i] {
+				if p.Start != nil {
+					p.Start(i)
+				}
+			}
+
+			before, beforeRan := job.Solver.Score(), ran[i]
+			if !job.Complete && ctx.Err() == nil {
+				generations := p.Slice
+				if generations < 1 {
+					generations = 1
+				}
+				if job.Generations != 0 && job.Generations-ran[i] < generations {
+					generations = job.Generations - ran[i]
+				}
+				job.Solver.Run(ctx, generations)
+			}
+
+			mu.Lock()
+			started[i] = true
+			busy[i] = false
+			pr := &progress[i]
+			pr.Score = job.Solver.Score()
+			pr.Generations = ran[i]
+			if ran[i] != beforeRan {
+				pr.Rate = (pr.Rate + float64(pr.Score-before)/float64(ran[i]-beforeRan)) / 2
+			}
+			o, ok := job.Solver.(interface{ Optimal() bool })
+			if job.Complete || (job.Generations != 0 && pr.Generations >= job.Generations) || (ok && o.Optimal()) {
+				finished[i] = true
+				left--
+			}
+			cond.Broadcast()
+			mu.Unlock()
+		}
+	}
+
+	workers := p.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			work()
+		}()
+	}
+	wg.Wait()
+	return progress
+}
@@ -0,0 +1,115 @@
+package schedule_test
+
+import (
+	"context"
+	mrand "math/rand"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/genetics"
+	"github.com/inlined/goldmine/pkg/maps"
+	"github.com/inlined/goldmine/pkg/schedule"
+	"github.com/inlined/goldmine/pkg/solver"
+)
+
+// counter is a Solver that scores a point per generation
+type counter struct {
+	solver.Observers
+	generations int
+	inits       int
+}
+
+func (c *counter) Init(popSize int) error {
+	c.inits++
+	return nil
+}
+
+func (c *counter) Step(count int) {
+	for i := 0; i < count; i++ {
+		c.Notify(solver.GenerationEvent{Generation: c.generations, Best: c.generations})
+		c.generations++
+	}
+}
+
+func (c *counter) Run(ctx context.Context, generations int) error {
+	return solver.RunSteps(ctx, c, generations)
+}
+
+func (c *counter) Path(genetics.Chromosome) maps.Path { return nil }
+func (c *counter) Score() int                         { return c.generations }
+func (c *counter) Best() genetics.Chromosome          { return genetics.Chromosome{} }
+func (c *counter) Checkpoint() solver.Checkpoint      { return solver.Checkpoint{} }
+func (c *counter) Restore(cp solver.Checkpoint) error { return nil }
+func (c *counter) Result() solver.Result              { return solver.Result{Score: c.generations} }
+
+func TestPool(t *testing.T) {
+	for _, name := range []string{"round-robin", "score", "improvement"} {
+		t.Run(name, func(t *testing.T) {
+			var f schedule.Flag
+			if err := f.Set(name); err != nil {
+				t.Fatal(err)
+			}
+			counters := []*counter{{}, {}, {}, {}}
+			jobs := []schedule.Job{
+				{Solver: counters[0], Generations: 25},
+				{Solver: counters[1], Generations: 7},
+				{Solver: counters[2], Generations: 30},
+				{Solver: counters[3], Generations: 30, Complete: true},
+			}
+
+			var mu sync.Mutex
+			var started []int
+			p := schedule.Pool{
+				Workers:   3,
+				Slice:     4,
+				Scheduler: f.Get(),
+				Rand:      mrand.New(mrand.NewSource(1)),
+				Start: func(i int) {
+					mu.Lock()
+					started = append(started, i)
+					mu.Unlock()
+					counters[i].Init(0)
+				},
+			}
+			progress := p.Run(context.Background(), jobs)
+
+			var got []int
+			for i, c := range counters {
+				got = append(got, c.generations)
+				if c.inits != 1 {
+					t.Errorf("map %d was started %d times", i, c.inits)
+				}
+				if progress[i].Generations != c.generations {
+					t.Errorf("map %d ran %d generations but reported %d", i, c.generations, progress[i].Generations)
+				}
+			}
+			if diff := cmp.Diff(got, []int{25, 7, 30, 0}); diff != "" {
+				t.Errorf("Run() ran the wrong number of generations; diff=%s", diff)
+			}
+			if len(started) != len(jobs) {
+				t.Errorf("started %v; want every map", started)
+			}
+		})
+	}
+}
+
+func TestPoolCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c := &counter{}
+	var f schedule.Flag
+	p := schedule.Pool{
+		Workers:   2,
+		Slice:     10,
+		Scheduler: f.Get(),
+		Rand:      mrand.New(mrand.NewSource(1)),
+		Start: func(i int) {
+			c.Init(0)
+		},
+	}
+	p.Run(ctx, []schedule.Job{{Solver: c}})
+	if c.inits != 1 || c.generations != 0 {
+		t.Errorf("canceled Pool started the map %d times and ran %d generations; want 1 and 0", c.inits, c.generations)
+	}
+}
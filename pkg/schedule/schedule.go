@@ -0,0 +1,145 @@
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/inlined/rand"
+)
+
+// Progress is what a Scheduler knows about each map
+type Progress struct {
+	Score       int
+	Generations int
+
+	// Rate is a moving average of the points gained per generation
+	Rate float64
+}
+
+// Scheduler decides which map gets the next slice of generations
+type Scheduler interface {
+	// Pick chooses one of candidates, which index progress and are never
+	// empty. Maps that haven't run yet are always picked first.
+	Pick(r rand.Rand, candidates []int, progress []Progress) int
+}
+
+// roundRobin gives every map the same number of generations
+type roundRobin struct{}
+
+func (roundRobin) Pick(r rand.Rand, candidates []int, progress []Progress) int {
+	best := candidates[0]
+	for _, i := range candidates[1:] {
+		if progress[i].Generations < progress[best].Generations {
+			best = i
+		}
+	}
+	return best
+}
+
+// scoreWeighted picks maps in proportion to their score, since points
+// on high scoring maps tend to be worth more.
+type scoreWeighted struct{}
+
+func (scoreWeighted) Pick(r rand.Rand, candidates []int, progress []Progress) int {
+	if i, ok := unstarted(candidates, progress); ok {
+		return i
+	}
+	weights := make([]float64, len(candidates))
+	for x, i := range candidates {
+		// +1 so that maps without points still get a chance
+		weights[x] = float64(progress[i].Score + 1)
+	}
+	return candidates[roulette(r, weights)]
+}
+
+// improvementWeighted picks maps in proportion to how quickly they have
+// been improving, where the marginal gain of more generations is highest.
+type improvementWeighted struct{}
+
+func (improvementWeighted) Pick(r rand.Rand, candidates []int, progress []Progress) int {
+	if i, ok := unstarted(candidates, progress); ok {
+		return i
+	}
+	weights := make([]float64, len(candidates))
+	most := 0.0
+	for x, i := range candidates {
+		weights[x] = progress[i].Rate
+		if weights[x] > most {
+			most = weights[x]
+		}
+	}
+	// Maps that have stalled still get an occasional slice in case
+	// they break out.
+	floor := most / 100
+	if floor == 0 {
+		floor = 1
+	}
+	for x := range weights {
+		weights[x] += floor
+	}
+	return candidates[roulette(r, weights)]
+}
+
+// unstarted finds the first candidate that hasn't run yet
+func unstarted(candidates []int, progress []Progress) (int, bool) {
+	for _, i := range candidates {
+		if progress[i].Generations == 0 {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// roulette picks an index of weights with probability in proportion to
+// its weight. Weights must be positive.
+func roulette(r rand.Rand, weights []float64) int {
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+	const precision = 1 << 30
+	target := total * float64(r.Int31n(precision)) / precision
+	for i, w := range weights {
+		if target < w {
+			return i
+		}
+		target -= w
+	}
+	return len(weights) - 1
+}
+
+var schedulers = map[string]Scheduler{
+	"round-robin": roundRobin{},
+	"score":       scoreWeighted{},
+	"improvement": improvementWeighted{},
+}
+
+// Flag allows users to pick a Scheduler with flag.Var
+type Flag string
+
+func (f Flag) String() string {
+	if f == "" {
+		return "improvement"
+	}
+	return string(f)
+}
+
+// Set implements flag.Value
+func (f *Flag) Set(s string) error {
+	if _, ok := schedulers[s]; !ok {
+		names := make([]string, 0, len(schedulers))
+		for name := range schedulers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("schedule.Flag.Set(%s): unknown scheduler; expected one of %s", s, strings.Join(names, ", "))
+	}
+	*f = Flag(s)
+	return nil
+}
+
+// Get returns the chosen Scheduler
+func (f Flag) Get() Scheduler {
+	return schedulers[f.String()]
+}
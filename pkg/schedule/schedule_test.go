@@ -0,0 +1,71 @@
+package schedule_test
+
+import (
+	mrand "math/rand"
+	"testing"
+
+	"github.com/inlined/goldmine/pkg/schedule"
+)
+
+func TestRoundRobin(t *testing.T) {
+	var f schedule.Flag
+	if err := f.Set("round-robin"); err != nil {
+		t.Fatal(err)
+	}
+	progress := []schedule.Progress{{Generations: 5}, {Generations: 3}, {Generations: 3}, {Generations: 1}}
+	if got := f.Get().Pick(mrand.New(mrand.NewSource(1)), []int{0, 1, 2}, progress); got != 1 {
+		t.Errorf("Pick() = %d; want the map with the fewest generations, 1", got)
+	}
+}
+
+func TestWeighted(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		progress []schedule.Progress
+		favorite int
+	}{
+		{
+			name:     "score",
+			progress: []schedule.Progress{{Score: 10, Generations: 1}, {Score: 1000, Generations: 1}},
+			favorite: 1,
+		}, {
+			name:     "improvement",
+			progress: []schedule.Progress{{Score: 1000, Generations: 1}, {Score: 10, Generations: 1, Rate: 5}},
+			favorite: 1,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var f schedule.Flag
+			if err := f.Set(test.name); err != nil {
+				t.Fatal(err)
+			}
+			r := mrand.New(mrand.NewSource(1))
+			counts := make([]int, len(test.progress))
+			for i := 0; i < 1000; i++ {
+				counts[f.Get().Pick(r, []int{0, 1}, test.progress)]++
+			}
+			if counts[test.favorite] < 900 {
+				t.Errorf("picked map %d %d of 1000 times; want at least 900", test.favorite, counts[test.favorite])
+			}
+			if counts[1-test.favorite] == 0 {
+				t.Errorf("never picked map %d; every map should get a chance", 1-test.favorite)
+			}
+
+			// Maps that haven't run come first
+			progress := append([]schedule.Progress{{}}, test.progress...)
+			if got := f.Get().Pick(r, []int{1, 2, 0}, progress); got != 0 {
+				t.Errorf("Pick() = %d; want the unstarted map 0", got)
+			}
+		})
+	}
+}
+
+func TestFlag(t *testing.T) {
+	var f schedule.Flag
+	if f.String() != "improvement" {
+		t.Errorf("default scheduler is %s; want improvement", f.String())
+	}
+	if err := f.Set("fastest"); err == nil {
+		t.Error("Set() accepted an unknown scheduler")
+	}
+}
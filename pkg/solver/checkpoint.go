@@ -0,0 +1,81 @@
+package solver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/inlined/genetics"
+)
+
+// Checkpoint is everything needed to resume a Solver where it left off
+type Checkpoint struct {
+	// Population holds the genes of each chromosome. Solvers that don't
+	// evolve a population leave it empty.
+	Population [][]genetics.Gene `json:"population,omitempty"`
+	Best       []genetics.Gene   `json:"best"`
+	Score      int               `json:"score"`
+	Generation int               `json:"generation"`
+	FoundAt    int               `json:"found_at"`
+
+	// Alternatives are the paths in the Solver's HallOfFame
+	Alternatives []string `json:"alternatives,omitempty"`
+
+	// Seed is the Input.Seed of the checkpointed Solver
+	Seed int64 `json:"seed"`
+
+	// Members holds the Checkpoints of Solvers that are made of other Solvers
+	Members []Checkpoint `json:"members,omitempty"`
+}
+
+// EncodeGenes copies the genes out of each of cs for a Checkpoint
+func EncodeGenes(cs []genetics.Chromosome) [][]genetics.Gene {
+	genes := make([][]genetics.Gene, len(cs))
+	for i, c := range cs {
+		genes[i] = append([]genetics.Gene(nil), c.Genes...)
+	}
+	return genes
+}
+
+// DecodeChromosome turns checkpointed genes back into a Chromosome of
+// species, failing if the number of genes doesn't match.
+func DecodeChromosome(species *genetics.Species, genes []genetics.Gene) (genetics.Chromosome, error) {
+	if len(genes) != species.NumGenes {
+		return genetics.Chromosome{}, fmt.Errorf("solver.DecodeChromosome(): checkpoint has %d genes but the species has %d", len(genes), species.NumGenes)
+	}
+	return genetics.Chromosome{Species: species, Genes: append([]genetics.Gene(nil), genes...)}, nil
+}
+
+// DecodeGenes is DecodeChromosome for a whole population
+func DecodeGenes(species *genetics.Species, genes [][]genetics.Gene) ([]genetics.Chromosome, error) {
+	cs := make([]genetics.Chromosome, len(genes))
+	for i, g := range genes {
+		var err error
+		if cs[i], err = DecodeChromosome(species, g); err != nil {
+			return nil, err
+		}
+	}
+	return cs, nil
+}
+
+// CheckpointFile is what the goldmine command saves between runs: one
+// Checkpoint per map, along with the strategy that made them.
+type CheckpointFile struct {
+	Strategy string       `json:"strategy"`
+	Seed     int64        `json:"seed"`
+	Maps     []Checkpoint `json:"maps"`
+}
+
+// WriteCheckpointFile writes f to w as JSON
+func WriteCheckpointFile(w io.Writer, f CheckpointFile) error {
+	return json.NewEncoder(w).Encode(f)
+}
+
+// ReadCheckpointFile reads a CheckpointFile written by WriteCheckpointFile
+func ReadCheckpointFile(r io.Reader) (CheckpointFile, error) {
+	var f CheckpointFile
+	if err := json.NewDecoder(r).Decode(&f); err != nil {
+		return f, fmt.Errorf("solver.ReadCheckpointFile(): %s", err)
+	}
+	return f, nil
+}
@@ -0,0 +1,58 @@
+package solver_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/genetics"
+	"github.com/inlined/goldmine/pkg/solver"
+)
+
+func TestCheckpointFile(t *testing.T) {
+	want := solver.CheckpointFile{
+		Strategy: "bruteforce",
+		Maps: []solver.Checkpoint{
+			{
+				Population: [][]genetics.Gene{{0, 1, 2}, {3, 2, 1}},
+				Best:       []genetics.Gene{3, 2, 1},
+				Score:      42,
+				Generation: 1000,
+				Seed:       -7,
+			},
+			{},
+		},
+	}
+	var buf bytes.Buffer
+	if err := solver.WriteCheckpointFile(&buf, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := solver.ReadCheckpointFile(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("ReadCheckpointFile() didn't read what was written; diff=%s", diff)
+	}
+}
+
+func TestDecodeGenes(t *testing.T) {
+	species := genetics.NewSpecies(3, 3)
+	genes := [][]genetics.Gene{{0, 1, 2}, {3, 2, 1}}
+	cs, err := solver.DecodeGenes(species, genes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, c := range cs {
+		if c.Species != species {
+			t.Errorf("chromosome %d has the wrong species", i)
+		}
+	}
+	if diff := cmp.Diff(solver.EncodeGenes(cs), genes); diff != "" {
+		t.Errorf("EncodeGenes(DecodeGenes()) changed the genes; diff=%s", diff)
+	}
+
+	if _, err := solver.DecodeGenes(species, [][]genetics.Gene{{0, 1}}); err == nil {
+		t.Error("DecodeGenes() accepted chromosomes with the wrong number of genes")
+	}
+}
@@ -0,0 +1,112 @@
+package solver
+
+import (
+	"github.com/inlined/genetics"
+
+	"github.com/inlined/goldmine/pkg/maps"
+)
+
+// Event is something a Solver reports while it works. Events are one of
+// InitEvent, GenerationEvent, ImprovementEvent, or DoneEvent.
+type Event interface {
+	event()
+}
+
+// InitEvent is sent once Init has prepared a Solver
+type InitEvent struct {
+	PointsOfInterest int
+
+	// MeaningfulPaths is the number of direct paths between points of
+	// interest, or 0 if the Solver doesn't precompute them.
+	MeaningfulPaths int
+
+	Genes          int
+	PopulationSize int
+}
+
+// GenerationEvent is sent after every generation. Solvers without a
+// population report their best score as Best, Mean, and Worst.
+type GenerationEvent struct {
+	Generation int
+	Best       int
+	Mean       float64
+	Worst      int
+}
+
+// ImprovementEvent is sent whenever a Solver finds a better path
+type ImprovementEvent struct {
+	Generation int
+	Score      int
+	Path       maps.Path
+}
+
+// DoneEvent is sent when Run returns. Err is what Run returned.
+type DoneEvent struct {
+	Generation int
+	Score      int
+	Err        error
+}
+
+func (InitEvent) event()        {}
+func (GenerationEvent) event()  {}
+func (ImprovementEvent) event() {}
+func (DoneEvent) event()        {}
+
+// NewGenerationEvent summarizes the fitness of a generation's population
+func NewGenerationEvent(generation int, fitness []genetics.Fitness) GenerationEvent {
+	e := GenerationEvent{Generation: generation}
+	if len(fitness) == 0 {
+		return e
+	}
+	sum := 0
+	e.Best, e.Worst = int(fitness[0]), int(fitness[0])
+	for _, f := range fitness {
+		x := int(f)
+		sum += x
+		if x > e.Best {
+			e.Best = x
+		}
+		if x < e.Worst {
+			e.Worst = x
+		}
+	}
+	e.Mean = float64(sum) / float64(len(fitness))
+	return e
+}
+
+// Observer receives the Events of the Solvers it is subscribed to.
+// Events are delivered synchronously from the Solver's goroutine.
+type Observer interface {
+	Observe(Event)
+}
+
+// ObserverFunc adapts a function into an Observer
+type ObserverFunc func(Event)
+
+// Observe implements Observer
+func (f ObserverFunc) Observe(e Event) {
+	f(e)
+}
+
+// Observers is embedded in Solvers to implement Subscribe
+type Observers struct {
+	observers []Observer
+}
+
+// Subscribe adds o to the Observers sent every future Event
+func (o *Observers) Subscribe(obs Observer) {
+	o.observers = append(o.observers, obs)
+}
+
+// Observed reports whether anyone is listening, so that Solvers can
+// skip preparing Events nobody will see.
+func (o *Observers) Observed() bool {
+	return len(o.observers) != 0
+}
+
+// Notify sends e to every subscribed Observer
+func (o *Observers) Notify(e Event) {
+	for _, obs := range o.observers {
+		obs.Observe(e)
+	}
+}
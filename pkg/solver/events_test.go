@@ -0,0 +1,63 @@
+package solver_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/genetics"
+	"github.com/inlined/goldmine/pkg/solver"
+)
+
+func TestNewGenerationEvent(t *testing.T) {
+	for _, test := range []struct {
+		tag     string
+		fitness []genetics.Fitness
+		want    solver.GenerationEvent
+	}{
+		{
+			tag:  "empty",
+			want: solver.GenerationEvent{Generation: 7},
+		}, {
+			tag:     "one",
+			fitness: []genetics.Fitness{5},
+			want:    solver.GenerationEvent{Generation: 7, Best: 5, Mean: 5, Worst: 5},
+		}, {
+			tag:     "many",
+			fitness: []genetics.Fitness{4, 10, 0, 2},
+			want:    solver.GenerationEvent{Generation: 7, Best: 10, Mean: 4, Worst: 0},
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			got := solver.NewGenerationEvent(7, test.fitness)
+			if diff := cmp.Diff(got, test.want); diff != "" {
+				t.Errorf("NewGenerationEvent() got wrong summary; diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestObservers(t *testing.T) {
+	var o solver.Observers
+	if o.Observed() {
+		t.Error("Observed() = true with no subscribers")
+	}
+
+	var got []solver.Event
+	o.Subscribe(solver.ObserverFunc(func(e solver.Event) {
+		got = append(got, e)
+	}))
+	if !o.Observed() {
+		t.Error("Observed() = false after Subscribe")
+	}
+
+	want := []solver.Event{
+		solver.InitEvent{PointsOfInterest: 3},
+		solver.DoneEvent{Generation: 2, Score: 9},
+	}
+	for _, e := range want {
+		o.Notify(e)
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Observer got wrong events; diff=%s", diff)
+	}
+}
@@ -0,0 +1,201 @@
+package solver
+
+import (
+	"context"
+	"time"
+
+	"github.com/inlined/genetics"
+
+	"github.com/inlined/goldmine/pkg/maps"
+)
+
+// Genome describes how a Genetic Solver's chromosomes become paths
+type Genome struct {
+	Species *genetics.Species
+
+	// Path translates a chromosome of the population. It is only called
+	// while the Solver isn't running elsewhere, so it may reuse memory.
+	Path func(genetics.Chromosome) maps.Path
+
+	// Renew, if set, is called after each generation evolves and may
+	// replace chromosomes of the population
+	Renew func(population []genetics.Chromosome)
+
+	// Encode, if set, rewrites the genes of c, a chromosome of the
+	// population, so that it translates into p or a path like it
+	Encode func(c genetics.Chromosome, p maps.Path)
+}
+
+// Genetic implements the parts of a Solver that evolve a population of
+// chromosomes: stepping through generations, checkpointing, migrating,
+// and reporting results. Solvers embed it, implement Init and Path, and
+// call Start once Init has made the population.
+type Genetic struct {
+	Input
+	Observers
+
+	genome     Genome
+	eval       *maps.Evaluator
+	population []genetics.Chromosome
+	best       genetics.Chromosome
+	score      int
+	generation int
+
+	evaluations int
+	elapsed     time.Duration
+	found       int
+	hall        HallOfFame
+}
+
+// Start readies g to evolve population and reports stats as its InitEvent
+func (g *Genetic) Start(genome Genome, population []genetics.Chromosome, stats InitEvent) {
+	g.genome = genome
+	g.eval = maps.NewEvaluator(g.Map)
+	g.population = population
+	g.hall = HallOfFame{Size: g.Alternatives}
+	g.Notify(stats)
+}
+
+// Step iterates through count generations of evolution,
+// updating the population, score, and best path
+func (g *Genetic) Step(count int) {
+	start := time.Now()
+	defer func() {
+		g.elapsed += time.Since(start)
+	}()
+	fitness := make([]genetics.Fitness, len(g.population))
+	for i := 0; i < count; i++ {
+		for n, c := range g.population {
+			fitness[n] = genetics.Fitness(g.evaluate(c))
+		}
+		if g.Observed() {
+			g.Notify(NewGenerationEvent(g.generation, fitness))
+		}
+		g.generation++
+		g.Evolver.Evolve(g.Rand, g.population, fitness)
+		if g.genome.Renew != nil {
+			g.genome.Renew(g.population)
+		}
+	}
+}
+
+// evaluate scores c and keeps it if it is the best so far
+func (g *Genetic) evaluate(c genetics.Chromosome) int {
+	path := g.genome.Path(c)
+	score := g.eval.Evaluate(path)
+	g.evaluations++
+	g.hall.Offer(path, score)
+	if g.best.Species == nil || score > g.score {
+		g.score = score
+		g.best = c
+		g.found = g.generation
+		if g.Observed() {
+			g.Notify(ImprovementEvent{Generation: g.generation, Score: score, Path: path})
+		}
+	}
+	return score
+}
+
+// Run steps through generations until they are done or ctx is done
+func (g *Genetic) Run(ctx context.Context, generations int) error {
+	err := RunSteps(ctx, g, generations)
+	g.Notify(DoneEvent{Generation: g.generation, Score: g.score, Err: err})
+	return err
+}
+
+// Checkpoint saves the population, best chromosome, and progress
+func (g *Genetic) Checkpoint() Checkpoint {
+	cp := Checkpoint{
+		Population: EncodeGenes(g.population),
+		Score:      g.score,
+		Generation: g.generation,
+		FoundAt:    g.found,
+		Seed:       g.Seed,
+
+		Alternatives: g.hall.Checkpoint(),
+	}
+	if g.best.Species != nil {
+		cp.Best = append(cp.Best, g.best.Genes...)
+	}
+	return cp
+}
+
+// Restore resumes from a Checkpoint. The best chromosome is rescored
+// rather than trusting the saved score.
+func (g *Genetic) Restore(cp Checkpoint) error {
+	population, err := DecodeGenes(g.genome.Species, cp.Population)
+	if err != nil {
+		return err
+	}
+	if len(population) != 0 {
+		g.population = population
+	}
+	if cp.Best != nil {
+		if g.best, err = DecodeChromosome(g.genome.Species, cp.Best); err != nil {
+			return err
+		}
+		g.score = g.fitness(g.best)
+		g.found = cp.FoundAt
+	}
+	g.generation = cp.Generation
+	g.hall.Restore(g.Map, cp.Alternatives)
+	return nil
+}
+
+// fitness scores a chromosome of the population
+func (g *Genetic) fitness(c genetics.Chromosome) int {
+	return g.eval.Evaluate(g.genome.Path(c))
+}
+
+// Emigrants copies the genes of the k fittest chromosomes
+func (g *Genetic) Emigrants(k int) [][]genetics.Gene {
+	return Emigrants(g.population, g.fitness, k)
+}
+
+// Immigrate replaces the least fit chromosomes with genes
+func (g *Genetic) Immigrate(genes [][]genetics.Gene) error {
+	return Immigrate(g.genome.Species, g.population, g.fitness, genes)
+}
+
+// Adopt replaces the least fit chromosome with one encoding p, a path
+// found elsewhere, and scores it right away. It does nothing if the
+// Genome can't Encode paths.
+func (g *Genetic) Adopt(p maps.Path) {
+	if g.genome.Encode == nil || len(g.population) == 0 {
+		return
+	}
+	order := rank(g.population, g.fitness)
+	worst := order[len(order)-1]
+	c := g.population[worst]
+	c.Genes = append([]genetics.Gene(nil), c.Genes...)
+	g.genome.Encode(c, p)
+	g.population[worst] = c
+	g.evaluate(c)
+}
+
+// Result reports the best path and how much work went into finding it
+func (g *Genetic) Result() Result {
+	r := Result{
+		Score:       g.score,
+		Generations: g.generation,
+		Evaluations: g.evaluations,
+		Elapsed:     g.elapsed,
+		FoundAt:     g.found,
+
+		Alternatives: g.hall.Entries(),
+	}
+	if g.best.Species != nil {
+		r.Path = g.genome.Path(g.best)
+	}
+	return r
+}
+
+// Score accesses the current best score
+func (g *Genetic) Score() int {
+	return g.score
+}
+
+// Best reveals the winning Chromosome
+func (g *Genetic) Best() genetics.Chromosome {
+	return g.best
+}
@@ -0,0 +1,80 @@
+package solver
+
+import (
+	"github.com/inlined/goldmine/pkg/maps"
+)
+
+// Alternative is one of the paths kept by a HallOfFame
+type Alternative struct {
+	Path  maps.Path
+	Score int
+}
+
+// HallOfFame keeps the Size best scoring distinct paths, best first.
+// Paths are compared after decoding, so chromosomes that walk the same
+// way only take one place. The zero value keeps nothing.
+type HallOfFame struct {
+	Size    int
+	entries []Alternative
+}
+
+// Accepts is a cheap check of whether a path scoring score could be
+// admitted, to skip decoding paths that can't make it.
+func (h *HallOfFame) Accepts(score int) bool {
+	return h.Size > 0 && (len(h.entries) < h.Size || score > h.entries[len(h.entries)-1].Score)
+}
+
+// Offer adds a copy of p if it scores well enough and isn't already
+// kept. Paths that tie are ranked by which was offered first.
+func (h *HallOfFame) Offer(p maps.Path, score int) bool {
+	if !h.Accepts(score) {
+		return false
+	}
+	key := p.String()
+	at := len(h.entries)
+	for i, e := range h.entries {
+		if e.Path.String() == key {
+			return false
+		}
+		if at == len(h.entries) && score > e.Score {
+			at = i
+		}
+	}
+	h.entries = append(h.entries, Alternative{})
+	copy(h.entries[at+1:], h.entries[at:])
+	h.entries[at] = Alternative{Path: p.Copy(), Score: score}
+	if len(h.entries) > h.Size {
+		h.entries = h.entries[:h.Size]
+	}
+	return true
+}
+
+// Merge offers every path in alts
+func (h *HallOfFame) Merge(alts []Alternative) {
+	for _, a := range alts {
+		h.Offer(a.Path, a.Score)
+	}
+}
+
+// Entries lists the kept paths, best first
+func (h *HallOfFame) Entries() []Alternative {
+	return append([]Alternative(nil), h.entries...)
+}
+
+// Checkpoint saves the kept paths for Checkpoint.Alternatives
+func (h *HallOfFame) Checkpoint() []string {
+	var paths []string
+	for _, e := range h.entries {
+		paths = append(paths, e.Path.String())
+	}
+	return paths
+}
+
+// Restore offers checkpointed paths, rescoring them on m rather than
+// trusting the saved scores.
+func (h *HallOfFame) Restore(m maps.Map, paths []string) {
+	for _, s := range paths {
+		p := maps.ParsePath(s)
+		h.Offer(p, p.Score(m))
+	}
+}
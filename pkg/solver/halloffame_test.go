@@ -0,0 +1,77 @@
+package solver_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/goldmine/pkg/maps"
+	"github.com/inlined/goldmine/pkg/solver"
+)
+
+func TestHallOfFame(t *testing.T) {
+	type offer struct {
+		path  string
+		score int
+	}
+	for _, test := range []struct {
+		tag    string
+		size   int
+		offers []offer
+		want   []string
+	}{
+		{
+			tag:    "zero value",
+			offers: []offer{{"u", 1}},
+		}, {
+			tag:    "best first",
+			size:   3,
+			offers: []offer{{"u", 1}, {"d", 3}, {"l", 2}},
+			want:   []string{"d", "l", "u"},
+		}, {
+			tag:    "drops the worst",
+			size:   2,
+			offers: []offer{{"u", 1}, {"d", 3}, {"l", 2}, {"r", 0}},
+			want:   []string{"d", "l"},
+		}, {
+			tag:    "distinct paths",
+			size:   3,
+			offers: []offer{{"ud", 5}, {"du", 5}, {"ud", 5}},
+			want:   []string{"ud", "du"},
+		}, {
+			tag:    "ties keep the first offered",
+			size:   2,
+			offers: []offer{{"u", 1}, {"d", 1}, {"l", 1}},
+			want:   []string{"u", "d"},
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			h := solver.HallOfFame{Size: test.size}
+			for _, o := range test.offers {
+				h.Offer(maps.ParsePath(o.path), o.score)
+			}
+			got := h.Checkpoint()
+			if diff := cmp.Diff(got, test.want); diff != "" {
+				t.Errorf("HallOfFame kept the wrong paths; diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestHallOfFameRestore(t *testing.T) {
+	r := maps.NewReader(strings.NewReader(`=1,3,2
+		s19`))
+	m, err := r.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := solver.HallOfFame{Size: 2}
+	h.Restore(m, []string{"rl", "rr"})
+	want := []solver.Alternative{
+		{Path: maps.ParsePath("rr"), Score: 10},
+		{Path: maps.ParsePath("rl"), Score: 1},
+	}
+	if diff := cmp.Diff(h.Entries(), want); diff != "" {
+		t.Errorf("Restore() didn't rescore the paths; diff=%s", diff)
+	}
+}
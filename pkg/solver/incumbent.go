@@ -0,0 +1,80 @@
+package solver
+
+import (
+	"sync"
+
+	"github.com/inlined/genetics"
+
+	"github.com/inlined/goldmine/pkg/maps"
+)
+
+// Incumbent tracks the best chromosome found by any of several Solvers
+// running concurrently, remembering which Solver made it so that it can
+// be translated into a Path. It is safe for concurrent use.
+type Incumbent struct {
+	mu       sync.Mutex
+	owners   map[*genetics.Species]Solver
+	best     genetics.Chromosome
+	score    int
+	improved bool
+}
+
+// Offer makes sub's best chromosome the incumbent if score beats it.
+// sub must not be running. Ties go to whichever path sorts first, so the
+// incumbent doesn't depend on when each Solver was offered.
+func (inc *Incumbent) Offer(sub Solver, score int) {
+	c := sub.Best()
+	inc.mu.Lock()
+	defer inc.mu.Unlock()
+	if c.Species == nil || (inc.best.Species != nil && score < inc.score) {
+		return
+	}
+	if inc.best.Species != nil && score == inc.score {
+		owner := inc.owners[inc.best.Species]
+		if sub.Path(c).String() >= owner.Path(inc.best).String() {
+			return
+		}
+	}
+	if inc.owners == nil {
+		inc.owners = make(map[*genetics.Species]Solver)
+	}
+	// Copy the genes since sub may reuse them as it evolves
+	c.Genes = append([]genetics.Gene(nil), c.Genes...)
+	inc.owners[c.Species] = sub
+	inc.best, inc.score, inc.improved = c, score, true
+}
+
+// Improved reports whether the incumbent changed since the last call
+func (inc *Incumbent) Improved() bool {
+	inc.mu.Lock()
+	defer inc.mu.Unlock()
+	improved := inc.improved
+	inc.improved = false
+	return improved
+}
+
+// Score is the incumbent's score
+func (inc *Incumbent) Score() int {
+	inc.mu.Lock()
+	defer inc.mu.Unlock()
+	return inc.score
+}
+
+// Best is the incumbent chromosome
+func (inc *Incumbent) Best() genetics.Chromosome {
+	inc.mu.Lock()
+	defer inc.mu.Unlock()
+	return inc.best
+}
+
+// Path asks whichever Solver made c to translate it. c must have come
+// from Best.
+func (inc *Incumbent) Path(c genetics.Chromosome) maps.Path {
+	inc.mu.Lock()
+	owner, ok := inc.owners[c.Species]
+	inc.mu.Unlock()
+	if !ok {
+		panic("solver.Incumbent.Path(): chromosome wasn't offered to the Incumbent")
+	}
+	return owner.Path(c)
+}
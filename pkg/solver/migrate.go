@@ -0,0 +1,76 @@
+package solver
+
+import (
+	"sort"
+
+	"github.com/inlined/genetics"
+
+	"github.com/inlined/goldmine/pkg/maps"
+)
+
+// Migrator is a Solver with a population that can trade chromosomes
+// with other instances of the same Solver on the same map.
+type Migrator interface {
+	Solver
+
+	// Emigrants copies the genes of the k fittest chromosomes
+	Emigrants(k int) [][]genetics.Gene
+
+	// Immigrate replaces the least fit chromosomes with genes
+	Immigrate(genes [][]genetics.Gene) error
+}
+
+// Adopter is a Solver that can take in a path found by any other Solver
+// of the same map, such as the best of a portfolio.
+type Adopter interface {
+	Solver
+
+	// Adopt adds p to the search, keeping it as the best path if it is
+	Adopt(p maps.Path)
+}
+
+// rank orders the indexes of population from most to least fit
+func rank(population []genetics.Chromosome, score func(genetics.Chromosome) int) []int {
+	scores := make([]int, len(population))
+	order := make([]int, len(population))
+	for i, c := range population {
+		scores[i] = score(c)
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return scores[order[i]] > scores[order[j]]
+	})
+	return order
+}
+
+// Emigrants implements Migrator.Emigrants for Solvers that can score
+// the chromosomes of their population.
+func Emigrants(population []genetics.Chromosome, score func(genetics.Chromosome) int, k int) [][]genetics.Gene {
+	order := rank(population, score)
+	if k > len(order) {
+		k = len(order)
+	}
+	genes := make([][]genetics.Gene, k)
+	for i := range genes {
+		genes[i] = append([]genetics.Gene(nil), population[order[i]].Genes...)
+	}
+	return genes
+}
+
+// Immigrate implements Migrator.Immigrate for Solvers that can score
+// the chromosomes of their population. If there are more genes than
+// chromosomes, the extras are ignored.
+func Immigrate(species *genetics.Species, population []genetics.Chromosome, score func(genetics.Chromosome) int, genes [][]genetics.Gene) error {
+	incoming, err := DecodeGenes(species, genes)
+	if err != nil {
+		return err
+	}
+	order := rank(population, score)
+	for i, c := range incoming {
+		if i == len(order) {
+			break
+		}
+		population[order[len(order)-1-i]] = c
+	}
+	return nil
+}
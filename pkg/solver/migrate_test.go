@@ -0,0 +1,46 @@
+package solver_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/genetics"
+	"github.com/inlined/goldmine/pkg/solver"
+)
+
+// sum scores a chromosome by adding up its genes
+func sum(c genetics.Chromosome) int {
+	total := 0
+	for _, g := range c.Genes {
+		total += int(g)
+	}
+	return total
+}
+
+func TestMigration(t *testing.T) {
+	species := genetics.NewSpecies(2, 9)
+	population, err := solver.DecodeGenes(species, [][]genetics.Gene{{1, 1}, {5, 5}, {0, 0}, {3, 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]genetics.Gene{{5, 5}, {3, 3}}
+	if diff := cmp.Diff(solver.Emigrants(population, sum, 2), want); diff != "" {
+		t.Errorf("Emigrants() didn't pick the fittest; diff=%s", diff)
+	}
+	if got := solver.Emigrants(population, sum, 10); len(got) != len(population) {
+		t.Errorf("Emigrants(10) returned %d chromosomes; want all %d", len(got), len(population))
+	}
+
+	if err := solver.Immigrate(species, population, sum, [][]genetics.Gene{{9, 9}, {8, 8}}); err != nil {
+		t.Fatal(err)
+	}
+	want = [][]genetics.Gene{{8, 8}, {5, 5}, {9, 9}, {3, 3}}
+	if diff := cmp.Diff(solver.EncodeGenes(population), want); diff != "" {
+		t.Errorf("Immigrate() didn't replace the least fit; diff=%s", diff)
+	}
+
+	if err := solver.Immigrate(species, population, sum, [][]genetics.Gene{{1}}); err == nil {
+		t.Error("Immigrate() accepted genes from another species")
+	}
+}
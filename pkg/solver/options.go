@@ -0,0 +1,177 @@
+package solver
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OptionType is the type of value an Option holds
+type OptionType int
+
+const (
+	// IntOption values are parsed with strconv.Atoi
+	IntOption OptionType = iota + 1
+	// FloatOption values are parsed with strconv.ParseFloat
+	FloatOption
+	// BoolOption values are parsed with strconv.ParseBool
+	BoolOption
+	// StringOption values are used as is
+	StringOption
+)
+
+func (t OptionType) String() string {
+	switch t {
+	case IntOption:
+		return "int"
+	case FloatOption:
+		return "float"
+	case BoolOption:
+		return "bool"
+	case StringOption:
+		return "string"
+	default:
+		return fmt.Sprintf("OptionType(%d)", int(t))
+	}
+}
+
+// parse converts s into a value of type t
+func (t OptionType) parse(s string) (interface{}, error) {
+	switch t {
+	case IntOption:
+		return strconv.Atoi(s)
+	case FloatOption:
+		return strconv.ParseFloat(s, 64)
+	case BoolOption:
+		return strconv.ParseBool(s)
+	case StringOption:
+		return s, nil
+	default:
+		return nil, fmt.Errorf("unknown option type %s", t)
+	}
+}
+
+// Option describes a parameter that a Solver accepts
+type Option struct {
+	Name        string
+	Type        OptionType
+	Default     string
+	Description string
+}
+
+// Options holds the value of every Option a Solver accepts, typed as
+// its OptionType says. The getters panic if asked for the wrong type
+// or a name that isn't in the Solver's schema.
+type Options map[string]interface{}
+
+// Int returns the value of an IntOption
+func (o Options) Int(name string) int {
+	return o[name].(int)
+}
+
+// Float returns the value of a FloatOption
+func (o Options) Float(name string) float64 {
+	return o[name].(float64)
+}
+
+// Bool returns the value of a BoolOption
+func (o Options) Bool(name string) bool {
+	return o[name].(bool)
+}
+
+// String returns the value of a StringOption
+func (o Options) String(name string) string {
+	return o[name].(string)
+}
+
+// parseSpec splits a spec like "graph(alleles=3,foo=bar)" into its name
+// and settings. A spec without parentheses has no settings.
+func parseSpec(spec string) (string, map[string]string, error) {
+	open := strings.IndexByte(spec, '(')
+	if open == -1 {
+		return spec, nil, nil
+	}
+	if !strings.HasSuffix(spec, ")") {
+		return "", nil, fmt.Errorf("%q is missing a closing parenthesis", spec)
+	}
+	name := spec[:open]
+	settings := make(map[string]string)
+	body := spec[open+1 : len(spec)-1]
+	if strings.TrimSpace(body) == "" {
+		return name, settings, nil
+	}
+	kvs, err := splitOptions(body)
+	if err != nil {
+		return "", nil, fmt.Errorf("%q: %s", spec, err)
+	}
+	for _, kv := range kvs {
+		eq := strings.IndexByte(kv, '=')
+		if eq == -1 {
+			return "", nil, fmt.Errorf("option %q should look like name=value", kv)
+		}
+		key, value := strings.TrimSpace(kv[:eq]), strings.TrimSpace(kv[eq+1:])
+		if _, ok := settings[key]; ok {
+			return "", nil, fmt.Errorf("option %s is set twice", key)
+		}
+		settings[key] = value
+	}
+	return name, settings, nil
+}
+
+// splitOptions splits a list of options on the commas that aren't inside
+// the parentheses of a nested spec, such as portfolio's solvers
+func splitOptions(body string) ([]string, error) {
+	var kvs []string
+	depth, start := 0, 0
+	for i, c := range body {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return nil, fmt.Errorf("unexpected closing parenthesis")
+			}
+			depth--
+		case ',':
+			if depth == 0 {
+				kvs = append(kvs, body[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("nested spec is missing a closing parenthesis")
+	}
+	return append(kvs, body[start:]), nil
+}
+
+// resolve type checks settings against schema and fills in defaults
+func resolve(schema []Option, settings map[string]string) (Options, error) {
+	known := make(map[string]bool, len(schema))
+	o := make(Options, len(schema))
+	for _, opt := range schema {
+		known[opt.Name] = true
+		s, ok := settings[opt.Name]
+		if !ok {
+			s = opt.Default
+		}
+		v, err := opt.Type.parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("option %s must be a %s: %s", opt.Name, opt.Type, err)
+		}
+		o[opt.Name] = v
+	}
+
+	var unknown []string
+	for key := range settings {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) != 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown options %s", strings.Join(unknown, ", "))
+	}
+	return o, nil
+}
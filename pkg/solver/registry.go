@@ -0,0 +1,98 @@
+package solver
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Descriptor tells a Registry how to make and explain a Solver
+type Descriptor struct {
+	Name        string
+	Description string
+	Options     []Option
+
+	// New creates a Solver. opts has a value for every Option.
+	New func(i Input, opts Options) Solver
+}
+
+// Registry is a set of Solvers that can be created by name
+type Registry struct {
+	descriptors map[string]Descriptor
+}
+
+// DefaultRegistry holds every Solver package that has been imported.
+// Solver packages register themselves here in their init() functions.
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry creates an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{descriptors: make(map[string]Descriptor)}
+}
+
+// Register adds a Solver to r. It panics if the name is taken or an
+// Option's default doesn't parse, since both are programming errors.
+func (r *Registry) Register(d Descriptor) {
+	if _, ok := r.descriptors[d.Name]; ok {
+		panic(fmt.Sprintf("Double registering solver %s", d.Name))
+	}
+	if _, err := resolve(d.Options, nil); err != nil {
+		panic(fmt.Sprintf("Solver %s has a bad default: %s", d.Name, err))
+	}
+	r.descriptors[d.Name] = d
+}
+
+// Lookup finds the Descriptor registered as name
+func (r *Registry) Lookup(name string) (Descriptor, bool) {
+	d, ok := r.descriptors[name]
+	return d, ok
+}
+
+// Descriptors lists everything in r sorted by name
+func (r *Registry) Descriptors() []Descriptor {
+	ds := make([]Descriptor, 0, len(r.descriptors))
+	for _, d := range r.descriptors {
+		ds = append(ds, d)
+	}
+	sort.Slice(ds, func(i, j int) bool {
+		return ds[i].Name < ds[j].Name
+	})
+	return ds
+}
+
+// Parse looks up the Solver named by a spec such as "graph(alleles=3)"
+// and type checks its options. Options that aren't set get their defaults.
+func (r *Registry) Parse(spec string) (Descriptor, Options, error) {
+	name, settings, err := parseSpec(spec)
+	if err != nil {
+		return Descriptor{}, nil, fmt.Errorf("solver.Registry.Parse(%s): %s", spec, err)
+	}
+	d, ok := r.descriptors[name]
+	if !ok {
+		return Descriptor{}, nil, fmt.Errorf("solver.Registry.Parse(%s): unknown solver %s", spec, name)
+	}
+	opts, err := resolve(d.Options, settings)
+	if err != nil {
+		return Descriptor{}, nil, fmt.Errorf("solver.Registry.Parse(%s): %s", spec, err)
+	}
+	return d, opts, nil
+}
+
+// New creates the Solver described by spec, as understood by Parse
+func (r *Registry) New(spec string, i Input) (Solver, error) {
+	d, opts, err := r.Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+	return d.New(i, opts), nil
+}
+
+// Help writes a description of every Solver and its Options to w
+func (r *Registry) Help(w io.Writer) {
+	for _, d := range r.Descriptors() {
+		fmt.Fprintf(w, "%s: %s\n", d.Name, d.Description)
+		for _, o := range d.Options {
+			fmt.Fprintf(w, "    %s=%s (%s): %s\n", o.Name, o.Default, o.Type, o.Description)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package solver_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/goldmine/pkg/solver"
+)
+
+func testRegistry() *solver.Registry {
+	r := solver.NewRegistry()
+	r.Register(solver.Descriptor{
+		Name:        "test",
+		Description: "a solver for tests",
+		Options: []solver.Option{
+			{Name: "count", Type: solver.IntOption, Default: "3", Description: "a count"},
+			{Name: "ratio", Type: solver.FloatOption, Default: "0.5", Description: "a ratio"},
+			{Name: "fast", Type: solver.BoolOption, Default: "false", Description: "go fast"},
+			{Name: "mode", Type: solver.StringOption, Default: "ring", Description: "a mode"},
+		},
+	})
+	r.Register(solver.Descriptor{Name: "plain", Description: "no options"})
+	return r
+}
+
+func TestParse(t *testing.T) {
+	for _, test := range []struct {
+		tag  string
+		spec string
+		name string
+		opts solver.Options
+	}{
+		{
+			tag:  "no options",
+			spec: "plain",
+			name: "plain",
+			opts: solver.Options{},
+		}, {
+			tag:  "defaults",
+			spec: "test",
+			name: "test",
+			opts: solver.Options{"count": 3, "ratio": 0.5, "fast": false, "mode": "ring"},
+		}, {
+			tag:  "empty parentheses",
+			spec: "test()",
+			name: "test",
+			opts: solver.Options{"count": 3, "ratio": 0.5, "fast": false, "mode": "ring"},
+		}, {
+			tag:  "overrides",
+			spec: "test(count=7, fast=true,mode=full)",
+			name: "test",
+			opts: solver.Options{"count": 7, "ratio": 0.5, "fast": true, "mode": "full"},
+		}, {
+			tag:  "nested spec",
+			spec: "test(mode=test(count=1,fast=true)+plain,count=2)",
+			name: "test",
+			opts: solver.Options{"count": 2, "ratio": 0.5, "fast": false, "mode": "test(count=1,fast=true)+plain"},
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			d, opts, err := testRegistry().Parse(test.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d.Name != test.name {
+				t.Errorf("Parse(%s) found solver %s; want %s", test.spec, d.Name, test.name)
+			}
+			if diff := cmp.Diff(opts, test.opts); diff != "" {
+				t.Errorf("Parse(%s) got wrong options; diff=%s", test.spec, diff)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{
+		"missing",
+		"test(count=3",
+		"test(mode=test(count=1)",
+		"test(mode=plain),count=1)",
+		"test(count)",
+		"test(count=three)",
+		"test(count=1,count=2)",
+		"test(colour=red)",
+		"plain(count=1)",
+	} {
+		t.Run(spec, func(t *testing.T) {
+			if _, _, err := testRegistry().Parse(spec); err == nil {
+				t.Errorf("Parse(%s) should have failed", spec)
+			}
+		})
+	}
+}
+
+func TestHelp(t *testing.T) {
+	var buf bytes.Buffer
+	testRegistry().Help(&buf)
+	want := `plain: no options
+test: a solver for tests
+    count=3 (int): a count
+    ratio=0.5 (float): a ratio
+    fast=false (bool): go fast
+    mode=ring (string): a mode
+`
+	if diff := cmp.Diff(buf.String(), want); diff != "" {
+		t.Errorf("Help() wrote the wrong text; diff=%s", diff)
+	}
+}
+
+func TestFlag(t *testing.T) {
+	f := solver.Flag{Registry: testRegistry()}
+	if f.String() != "bruteforce" || f.Name() != "bruteforce" {
+		t.Errorf("default Flag is %s (%s); want bruteforce", f.String(), f.Name())
+	}
+	if err := f.Set("test(count=1)"); err != nil {
+		t.Fatal(err)
+	}
+	if f.String() != "test(count=1)" || f.Name() != "test" {
+		t.Errorf("Flag is %s (%s); want test(count=1) (test)", f.String(), f.Name())
+	}
+	if err := f.Set("missing"); err == nil {
+		t.Error("Set() accepted an unknown solver")
+	}
+	if err := f.Set("help"); err != nil || !f.Help() {
+		t.Errorf("Set(help) = %v; Help() = %t", err, f.Help())
+	}
+}
@@ -0,0 +1,37 @@
+package solver
+
+import (
+	"time"
+
+	"github.com/inlined/goldmine/pkg/maps"
+)
+
+// Result summarizes what a Solver has found so far. Generations includes
+// any restored from a Checkpoint, but Evaluations and Elapsed only count
+// work done since Init.
+type Result struct {
+	Path  maps.Path
+	Score int
+
+	// UpperBound is a score no path on the map can beat, or 0 if the
+	// Solver doesn't know one.
+	UpperBound int
+
+	// Optimal is set once Score is proven to be the best possible
+	Optimal bool
+
+	Generations int
+
+	// Evaluations is how many paths or search nodes were scored
+	Evaluations int
+
+	// Elapsed is the time spent in Step and Run
+	Elapsed time.Duration
+
+	// FoundAt is the generation the best path was found in
+	FoundAt int
+
+	// Alternatives are the best distinct paths found, best first, up to
+	// Input.Alternatives of them
+	Alternatives []Alternative
+}
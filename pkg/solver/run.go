@@ -0,0 +1,22 @@
+package solver
+
+import "context"
+
+// Stepper is anything that can evolve one generation at a time
+type Stepper interface {
+	Step(count int)
+}
+
+// RunSteps implements Solver.Run by calling s.Step one generation at a
+// time and checking ctx in between.
+func RunSteps(ctx context.Context, s Stepper, generations int) error {
+	for done := 0; generations == 0 || done < generations; done++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		s.Step(1)
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package solver
+
+import (
+	mrand "math/rand"
+
+	"github.com/inlined/rand"
+)
+
+// DeriveSeed mixes a run's seed with an index, such as a map's position
+// in the input, so that each map gets its own reproducible Rand no matter
+// what order maps are solved in.
+func DeriveSeed(seed int64, i int) int64 {
+	// splitmix64 finalizer
+	x := uint64(seed) + uint64(i+1)*0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return int64(x ^ (x >> 31))
+}
+
+// NewRand creates a Rand that always produces the same numbers for seed
+func NewRand(seed int64) rand.Rand {
+	return mrand.New(mrand.NewSource(seed))
+}
@@ -0,0 +1,31 @@
+package solver_test
+
+import (
+	"testing"
+
+	"github.com/inlined/goldmine/pkg/solver"
+)
+
+func TestDeriveSeed(t *testing.T) {
+	seen := make(map[int64]int)
+	for i := 0; i < 100; i++ {
+		s := solver.DeriveSeed(42, i)
+		if s != solver.DeriveSeed(42, i) {
+			t.Fatalf("DeriveSeed(42, %d) isn't deterministic", i)
+		}
+		if j, ok := seen[s]; ok {
+			t.Errorf("DeriveSeed(42, %d) == DeriveSeed(42, %d)", i, j)
+		}
+		seen[s] = i
+	}
+	if solver.DeriveSeed(1, 0) == solver.DeriveSeed(2, 0) {
+		t.Error("DeriveSeed ignores the run's seed")
+	}
+
+	a, b := solver.NewRand(7), solver.NewRand(7)
+	for i := 0; i < 10; i++ {
+		if x, y := a.Int31n(1000), b.Int31n(1000); x != y {
+			t.Fatalf("NewRand(7) gave %d then %d", x, y)
+		}
+	}
+}
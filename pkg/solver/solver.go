@@ -1,6 +1,7 @@
 package solver
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/inlined/genetics"
@@ -9,19 +10,30 @@ import (
 	"github.com/inlined/goldmine/pkg/maps"
 )
 
-// This is a pretty bad violation of my normal rules against
-// globals. Is there a better way?
-var (
-	factories = make(map[string]func(i Input) Solver)
-)
-
 // Solver is the generic interface for all solvers
 type Solver interface {
 	Init(popSize int) error
 	Step(count int)
+
+	// Run steps through up to generations generations, or until ctx is
+	// done if generations is 0. Returns ctx.Err() if ctx ended the run early;
+	// the solver keeps its progress either way.
+	Run(ctx context.Context, generations int) error
+
+	// Subscribe sends o the Events of every later Init, Step, and Run
+	Subscribe(o Observer)
+
 	Path(genetics.Chromosome) maps.Path
 	Score() int
 	Best() genetics.Chromosome
+
+	// Result reports the best Path and statistics about the search
+	Result() Result
+
+	// Checkpoint saves the Solver's progress. Restore loads it into a
+	// Solver that has just been through Init.
+	Checkpoint() Checkpoint
+	Restore(Checkpoint) error
 }
 
 // Input is used to create a solver
@@ -29,40 +41,83 @@ type Input struct {
 	Map     maps.Map
 	Evolver genetics.Evolver
 	Rand    rand.Rand
+
+	// Seed is what Rand was seeded with, if known, so that it can be
+	// saved in checkpoints.
+	Seed int64
+
+	// Alternatives is how many distinct paths Result should list
+	Alternatives int
+
+	// WarmStart holds valid paths, such as an earlier run's answers,
+	// for Init to seed the search with.
+	WarmStart []maps.Path
+}
+
+// Flag allows developers to specify a Solver via flag and create
+// instances with New(). Values look like "graph" or "graph(alleles=3)",
+// and "help" asks for a list of every Solver.
+type Flag struct {
+	// Registry to look Solvers up in; nil means DefaultRegistry
+	Registry *Registry
+
+	spec string
+	desc Descriptor
+	opts Options
 }
 
-// Flag allows developers to specify a Solver via
-// flag and create instances with New()
-type Flag string
+const (
+	defaultSpec = "bruteforce"
+	helpSpec    = "help"
+)
+
+func (f *Flag) registry() *Registry {
+	if f.Registry == nil {
+		return DefaultRegistry
+	}
+	return f.Registry
+}
 
-func (f Flag) String() string {
-	if f == "" {
-		return "bruteforce"
+func (f *Flag) String() string {
+	if f == nil || f.spec == "" {
+		return defaultSpec
 	}
-	return string(f)
+	return f.spec
 }
 
 // Set implements flag.Value
 func (f *Flag) Set(s string) error {
-	if _, ok := factories[s]; !ok {
-		return fmt.Errorf("solver.Flag.Set(%s) unknown solver %s", s, s)
+	if s == helpSpec {
+		f.spec = s
+		return nil
 	}
-	*f = Flag(s)
-
+	d, opts, err := f.registry().Parse(s)
+	if err != nil {
+		return fmt.Errorf("solver.Flag.Set(%s): %s", s, err)
+	}
+	f.spec, f.desc, f.opts = s, d, opts
 	return nil
 }
 
-// New creates a new Solver with solver.Input
-func (f Flag) New(i Input) Solver {
-	name := f.String()
-	return factories[name](i)
+// Help reports whether the flag asked for a list of Solvers
+func (f *Flag) Help() bool {
+	return f.spec == helpSpec
+}
+
+// Name is the name of the chosen Solver without its options
+func (f *Flag) Name() string {
+	if f.desc.Name == "" {
+		return defaultSpec
+	}
+	return f.desc.Name
 }
 
-// RegisterSolverFlag is to be called in a solver package's init()
-// function so that solver.Flag can include that solver in the parser.
-func RegisterSolverFlag(flag string, f func(Input) Solver) {
-	if _, ok := factories[flag]; ok {
-		panic(fmt.Sprintf("Double registering factory %s", flag))
+// New creates a new Solver with solver.Input
+func (f *Flag) New(i Input) Solver {
+	if f.desc.New == nil {
+		if err := f.Set(f.String()); err != nil {
+			panic(err)
+		}
 	}
-	factories[flag] = f
+	return f.desc.New(i, f.opts)
 }
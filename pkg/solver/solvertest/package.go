@@ -0,0 +1,4 @@
+// Package solvertest is a conformance suite for Solvers. Any Solver in
+// a Registry can be checked with Run, and RunAll checks every Solver
+// registered with solver.DefaultRegistry.
+package solvertest
@@ -0,0 +1,178 @@
+package solvertest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/inlined/genetics"
+
+	"github.com/inlined/goldmine/pkg/maps"
+	"github.com/inlined/goldmine/pkg/solver"
+)
+
+const (
+	populationSize = 20
+	steps          = 10
+	seed           = 42
+)
+
+// Maps are the maps every Solver is checked against, by name
+var Maps = []struct {
+	Name string
+	Map  string
+}{
+	{
+		Name: "open",
+		Map: `=4,5,8
+			w...1
+			..s.9
+			2d1..
+			.w..3`,
+	}, {
+		Name: "only start",
+		Map: `=2,3,5
+			.s.
+			...`,
+	}, {
+		Name: "boxed in",
+		Map: `=3,3,4
+			.w.
+			wsw
+			.w9`,
+	}, {
+		Name: "corridor",
+		Map: `=3,5,9
+			wwwww
+			1s.d9
+			wwwww`,
+	}, {
+		Name: "walled off",
+		Map: `=3,5,6
+			s.w.9
+			..w..
+			.dw.1`,
+	},
+}
+
+// RunAll runs the suite against every Solver in solver.DefaultRegistry
+// with its default options.
+func RunAll(t *testing.T) {
+	for _, d := range solver.DefaultRegistry.Descriptors() {
+		t.Run(d.Name, func(t *testing.T) {
+			Run(t, d.Name)
+		})
+	}
+}
+
+// Run checks that, on each of Maps, the Solver made from spec by
+// solver.DefaultRegistry returns valid paths that are exactly
+// StepsAllowed long, scores its best path the same as the map does,
+// never loses score between Steps, and does the same thing every time
+// it is given the same seed.
+func Run(t *testing.T, spec string) {
+	for _, test := range Maps {
+		t.Run(test.Name, func(t *testing.T) {
+			r := maps.NewReader(strings.NewReader(test.Map))
+			m, err := r.Next()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s := start(t, spec, m)
+			score := s.Score()
+			for i := 0; i < steps; i++ {
+				s.Step(1)
+				if s.Score() < score {
+					t.Fatalf("score fell from %d to %d in step %d", score, s.Score(), i)
+				}
+				score = s.Score()
+				checkBest(t, m, s)
+			}
+
+			res := s.Result()
+			checkPath(t, m, "Result().Path", res.Path)
+			if res.Score != s.Score() {
+				t.Errorf("Result().Score = %d; want Score() = %d", res.Score, s.Score())
+			}
+			for _, a := range res.Alternatives {
+				checkPath(t, m, "alternative", a.Path)
+				if score := a.Path.Score(m); score != a.Score {
+					t.Errorf("alternative %s claims %d points but scores %d", a.Path, a.Score, score)
+				}
+			}
+
+			again := start(t, spec, m)
+			again.Step(steps)
+			if again.Score() != s.Score() || again.Path(again.Best()).String() != s.Path(s.Best()).String() {
+				t.Errorf("same seed found %s scoring %d, then %s scoring %d", s.Path(s.Best()), s.Score(), again.Path(again.Best()), again.Score())
+			}
+		})
+	}
+}
+
+// start creates and initializes a Solver with the suite's seed
+func start(t *testing.T, spec string, m maps.Map) solver.Solver {
+	var selection genetics.NaturalSelectionFlag
+	var crossover genetics.CrossoverFlag
+	var mutation genetics.MutationFlag
+	s, err := solver.DefaultRegistry.New(spec, solver.Input{
+		Map: m,
+		Evolver: genetics.Evolver{
+			ReplacementCount: populationSize / 4,
+			MutationRate:     0.1,
+			Selector:         selection.Get(),
+			Crossover:        crossover.Get(),
+			Mutator:          mutation.Get(),
+		},
+		Rand:         solver.NewRand(seed),
+		Seed:         seed,
+		Alternatives: 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Init(populationSize); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// checkBest checks the path and score of the best chromosome
+func checkBest(t *testing.T, m maps.Map, s solver.Solver) {
+	t.Helper()
+	if s.Best().Species == nil {
+		if s.Score() != 0 {
+			t.Errorf("Score() = %d without a Best() chromosome", s.Score())
+		}
+		return
+	}
+	p := s.Path(s.Best())
+	checkPath(t, m, "Path(Best())", p)
+	if score := p.Score(m); score != s.Score() {
+		t.Errorf("Path(Best()) = %s scores %d; Score() = %d", p, score, s.Score())
+	}
+}
+
+// checkPath checks that p is a valid answer that uses every step, or
+// as many as it can when the start is boxed in.
+func checkPath(t *testing.T, m maps.Map, name string, p maps.Path) {
+	t.Helper()
+	err := p.Validate(m)
+	if pe, ok := err.(*maps.PathError); ok && pe.Reason == maps.TooShort && stuck(m) {
+		return
+	}
+	if err != nil {
+		t.Errorf("%s %s is invalid: %s", name, p, err)
+	}
+}
+
+// stuck reports whether there is nowhere to go from the start
+func stuck(m maps.Map) bool {
+	v := m.PointsOfInterest[0]
+	for _, d := range []maps.Direction{maps.Up, maps.Down, maps.Left, maps.Right} {
+		if m.CanBeAt(v.Move(d)) {
+			return false
+		}
+	}
+	return true
+}
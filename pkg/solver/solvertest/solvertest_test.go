@@ -0,0 +1,18 @@
+package solvertest_test
+
+import (
+	"testing"
+
+	"github.com/inlined/goldmine/pkg/solver/solvertest"
+
+	_ "github.com/inlined/goldmine/pkg/behavioral"
+	_ "github.com/inlined/goldmine/pkg/bruteforce"
+	_ "github.com/inlined/goldmine/pkg/exact"
+	_ "github.com/inlined/goldmine/pkg/graph"
+	_ "github.com/inlined/goldmine/pkg/island"
+	_ "github.com/inlined/goldmine/pkg/portfolio"
+)
+
+func TestRegistry(t *testing.T) {
+	solvertest.RunAll(t)
+}
@@ -0,0 +1,4 @@
+// Package stats summarizes repeated measurements, such as the scores of
+// a solver run with several seeds, and tests whether two sets of them
+// differ by more than chance.
+package stats
@@ -0,0 +1,135 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// Summary describes a set of measurements
+type Summary struct {
+	N      int
+	Mean   float64
+	Median float64
+
+	// StdDev is the sample standard deviation, or 0 with fewer than
+	// two measurements
+	StdDev float64
+}
+
+// Summarize describes xs
+func Summarize(xs []float64) Summary {
+	s := Summary{N: len(xs)}
+	if s.N == 0 {
+		return s
+	}
+	sorted := append([]float64(nil), xs...)
+	sort.Float64s(sorted)
+	if s.N%2 == 1 {
+		s.Median = sorted[s.N/2]
+	} else {
+		s.Median = (sorted[s.N/2-1] + sorted[s.N/2]) / 2
+	}
+	s.Mean = mean(xs)
+	if s.N > 1 {
+		s.StdDev = math.Sqrt(variance(xs, s.Mean))
+	}
+	return s
+}
+
+func mean(xs []float64) float64 {
+	sum := 0.0
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
+
+// variance is the sample variance of xs around their mean
+func variance(xs []float64, mean float64) float64 {
+	sum := 0.0
+	for _, x := range xs {
+		sum += (x - mean) * (x - mean)
+	}
+	return sum / float64(len(xs)-1)
+}
+
+// WelchT runs Welch's t-test of whether a and b have different means,
+// without assuming they have the same variance. It returns the t
+// statistic and the two-sided p-value: the chance of seeing a difference
+// at least this large if the means were really the same. p is 1 if
+// either set has fewer than two measurements.
+func WelchT(a, b []float64) (t, p float64) {
+	if len(a) < 2 || len(b) < 2 {
+		return 0, 1
+	}
+	ma, mb := mean(a), mean(b)
+	va := variance(a, ma) / float64(len(a))
+	vb := variance(b, mb) / float64(len(b))
+	if va+vb == 0 {
+		// Every measurement was the same within each set
+		if ma == mb {
+			return 0, 1
+		}
+		return math.Copysign(math.Inf(1), ma-mb), 0
+	}
+	t = (ma - mb) / math.Sqrt(va+vb)
+	df := (va + vb) * (va + vb) / (va*va/float64(len(a)-1) + vb*vb/float64(len(b)-1))
+	return t, betaInc(df/2, 0.5, df/(df+t*t))
+}
+
+// betaInc is the regularized incomplete beta function I_x(a, b),
+// evaluated with the continued fraction from Numerical Recipes.
+func betaInc(a, b, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+	lab, _ := math.Lgamma(a + b)
+	front := math.Exp(lab - la - lb + a*math.Log(x) + b*math.Log(1-x))
+	// The continued fraction converges quickly on this side, and
+	// I_x(a, b) = 1 - I_1-x(b, a) covers the other
+	if x > (a+1)/(a+b+2) {
+		return 1 - front*betaFraction(b, a, 1-x)/b
+	}
+	return front * betaFraction(a, b, x) / a
+}
+
+func betaFraction(a, b, x float64) float64 {
+	const (
+		iterations = 200
+		epsilon    = 1e-14
+		tiny       = 1e-300
+	)
+	c, d := 1.0, 1-(a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1.0; m <= iterations; m++ {
+		// Even then odd terms of the fraction
+		for _, num := range []float64{
+			m * (b - m) * x / ((a + 2*m - 1) * (a + 2*m)),
+			-(a + m) * (a + b + m) * x / ((a + 2*m) * (a + 2*m + 1)),
+		} {
+			d = 1 + num*d
+			if math.Abs(d) < tiny {
+				d = tiny
+			}
+			c = 1 + num/c
+			if math.Abs(c) < tiny {
+				c = tiny
+			}
+			d = 1 / d
+			h *= d * c
+		}
+		if math.Abs(d*c-1) < epsilon {
+			break
+		}
+	}
+	return h
+}
@@ -0,0 +1,90 @@
+package stats_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/inlined/goldmine/pkg/stats"
+)
+
+func TestSummarize(t *testing.T) {
+	for _, test := range []struct {
+		tag  string
+		xs   []float64
+		want stats.Summary
+	}{
+		{
+			tag: "empty",
+		}, {
+			tag:  "one",
+			xs:   []float64{3},
+			want: stats.Summary{N: 1, Mean: 3, Median: 3},
+		}, {
+			tag:  "odd",
+			xs:   []float64{5, 1, 3},
+			want: stats.Summary{N: 3, Mean: 3, Median: 3, StdDev: 2},
+		}, {
+			tag:  "even",
+			xs:   []float64{4, 1, 3, 8},
+			want: stats.Summary{N: 4, Mean: 4, Median: 3.5, StdDev: math.Sqrt(26.0 / 3)},
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			if diff := cmp.Diff(stats.Summarize(test.xs), test.want); diff != "" {
+				t.Errorf("Summarize(%v) was wrong; diff=%s", test.xs, diff)
+			}
+		})
+	}
+}
+
+func TestWelchT(t *testing.T) {
+	for _, test := range []struct {
+		tag   string
+		a, b  []float64
+		wantT float64
+		wantP float64
+	}{
+		{
+			// Two samples each with equal variance have 2 degrees of
+			// freedom, where P(|T| > t) = 1 - t/sqrt(2+t^2).
+			tag:   "small difference",
+			a:     []float64{0, 2},
+			b:     []float64{1, 3},
+			wantT: -1 / math.Sqrt2,
+			wantP: 1 - (1/math.Sqrt2)/math.Sqrt(2.5),
+		}, {
+			tag:   "large difference",
+			a:     []float64{2, 4},
+			b:     []float64{0, 2},
+			wantT: math.Sqrt2,
+			wantP: 1 - math.Sqrt2/2,
+		}, {
+			tag:   "same",
+			a:     []float64{1, 2, 3},
+			b:     []float64{3, 2, 1},
+			wantP: 1,
+		}, {
+			tag:   "no variance",
+			a:     []float64{5, 5},
+			b:     []float64{4, 4},
+			wantT: math.Inf(1),
+		}, {
+			tag:   "too few",
+			a:     []float64{5},
+			b:     []float64{1, 2},
+			wantP: 1,
+		},
+	} {
+		t.Run(test.tag, func(t *testing.T) {
+			gotT, gotP := stats.WelchT(test.a, test.b)
+			if !close(gotT, test.wantT) || !close(gotP, test.wantP) {
+				t.Errorf("WelchT(%v, %v) = %g, %g; want %g, %g", test.a, test.b, gotT, gotP, test.wantT, test.wantP)
+			}
+		})
+	}
+}
+
+func close(a, b float64) bool {
+	return a == b || math.Abs(a-b) < 1e-9
+}